@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/access"
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+var (
+	accessOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(accessCmd)
+	accessCmd.AddCommand(accessRepoCmd)
+	accessCmd.AddCommand(accessUserCmd)
+
+	accessCmd.PersistentFlags().StringVarP(&accessOutput, "output", "o", "table", "Output format, one of: table, json, csv")
+}
+
+var accessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Report the effective permissions of users on repositories, and what grants them",
+}
+
+var accessRepoCmd = &cobra.Command{
+	Use:   "repo REPOSITORY",
+	Short: "Report who can access a repository and why",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadAccessConfig()
+		if err != nil {
+			return err
+		}
+
+		accesses, err := access.ForRepository(cfg, config.RepositoryName(args[0]))
+		if err != nil {
+			return err
+		}
+		return printAccesses(accesses)
+	},
+}
+
+var accessUserCmd = &cobra.Command{
+	Use:   "user USER",
+	Short: "Report which repositories a user can access and why",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadAccessConfig()
+		if err != nil {
+			return err
+		}
+
+		login := args[0]
+		if _, isCollaborator := cfg.Collaborators[login]; !isCollaborator {
+			if login, err = findUser(cfg, args[0]); err != nil {
+				return err
+			}
+		}
+
+		accesses, err := access.ForUser(cfg, login)
+		if err != nil {
+			return err
+		}
+		return printAccesses(accesses)
+	},
+}
+
+func loadAccessConfig() (*config.Config, error) {
+	switch accessOutput {
+	case "table", "json", "csv":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", accessOutput)
+	}
+
+	cfg, err := loadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local state: %w", err)
+	}
+
+	if err = config.SanityCheck(cfg); err != nil {
+		return nil, fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+	return cfg, nil
+}
+
+func printAccesses(accesses []access.Access) error {
+	switch accessOutput {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(accesses)
+
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write([]string{"repository", "user", "permission", "grants"}); err != nil {
+			return err
+		}
+		for _, a := range accesses {
+			if err := w.Write([]string{a.Repository, a.User, a.Permission, grantsString(a.Grants, "; ")}); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "REPOSITORY\tUSER\tPERMISSION\tGRANTS\n")
+		for _, a := range accesses {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Repository, a.User, a.Permission, grantsString(a.Grants, ", "))
+		}
+		return w.Flush()
+	}
+}
+
+func grantsString(grants []access.Grant, sep string) string {
+	s := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		s = append(s, grant.String())
+	}
+	return strings.Join(s, sep)
+}
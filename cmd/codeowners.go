@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/google/renameio"
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/codeowners"
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+var (
+	codeownersRepo   string
+	codeownersOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(codeownersCmd)
+	codeownersCmd.AddCommand(checkCodeownersCmd)
+	codeownersCmd.AddCommand(generateCodeownersCmd)
+
+	codeownersCmd.PersistentFlags().StringVar(&codeownersRepo, "repo", "", "Repository of the CODEOWNERS file")
+	codeownersCmd.MarkPersistentFlagRequired("repo")
+	generateCodeownersCmd.Flags().StringVar(&codeownersOutput, "output", "", "File to write the CODEOWNERS file into, instead of the standard output")
+}
+
+var codeownersCmd = &cobra.Command{
+	Use:   "codeowners",
+	Short: "Check and generate CODEOWNERS files from the teams configuration",
+}
+
+var checkCodeownersCmd = &cobra.Command{
+	Use:   "check CODEOWNERS",
+	Short: "Check that all owners of a CODEOWNERS file exist and have write access to the repository",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rules, err := codeowners.Parse(f)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+
+		problems, err := codeowners.Check(cfg, config.RepositoryName(codeownersRepo), rules)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", args[0], problem)
+		}
+		if len(problems) != 0 {
+			return fmt.Errorf("%d owner(s) of %s would be ignored by GitHub", len(problems), args[0])
+		}
+		return nil
+	},
+}
+
+var generateCodeownersCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a CODEOWNERS file from the code owners declared by the teams",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		repoName := config.RepositoryName(codeownersRepo)
+		data, err := codeowners.Generate(cfg, repoName)
+		if err != nil {
+			return err
+		}
+
+		// Teams without write access would be ignored by GitHub.
+		rules, err := codeowners.Parse(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		problems, err := codeowners.Check(cfg, repoName, rules)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+		}
+
+		if codeownersOutput == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return renameio.WriteFile(codeownersOutput, data, 0o644)
+	},
+}
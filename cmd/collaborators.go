@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+var (
+	collaboratorsWithinDays    int
+	collaboratorsRemoveExpired bool
+)
+
+func init() {
+	rootCmd.AddCommand(collaboratorsCmd)
+	collaboratorsCmd.AddCommand(reviewCollaboratorsCmd)
+
+	reviewCollaboratorsCmd.Flags().IntVar(&collaboratorsWithinDays, "within-days", 30, "Also report outside collaborators whose access expires within this number of days")
+	reviewCollaboratorsCmd.Flags().BoolVar(&collaboratorsRemoveExpired, "remove-expired", false, "Remove the permissions of the expired outside collaborators from all repositories in local configuration")
+}
+
+var collaboratorsCmd = &cobra.Command{
+	Use:   "collaborators",
+	Short: "Manage the outside collaborators of the organization",
+}
+
+var reviewCollaboratorsCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Report expired and soon to expire outside collaborators",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		if err = config.SanityCheck(cfg); err != nil {
+			return fmt.Errorf("failed to perform sanity check: %w", err)
+		}
+
+		now := time.Now()
+		logins := config.ExpiringCollaborators(cfg, now.AddDate(0, 0, collaboratorsWithinDays))
+		if len(logins) == 0 {
+			fmt.Printf("No outside collaborators expire within %d days\n", collaboratorsWithinDays)
+			return nil
+		}
+
+		var expired []string
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "COLLABORATOR\tEXPIRES\tSTATUS\tSPONSOR\tREPOSITORIES\tREASON\n")
+		for _, login := range logins {
+			collaborator := cfg.Collaborators[login]
+			status := fmt.Sprintf("expires in %d days", int(collaborator.Expires.Sub(config.NewDate(now).Time).Hours()/24))
+			if collaborator.Expires.Expired(now) {
+				status = "expired"
+				expired = append(expired, login)
+			}
+			var repos []string
+			for _, repo := range cfg.UserRepositories(login) {
+				repos = append(repos, string(repo))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", login, collaborator.Expires, status, collaborator.Sponsor, strings.Join(repos, ","), collaborator.Reason)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if !collaboratorsRemoveExpired || len(expired) == 0 {
+			return nil
+		}
+
+		for _, login := range expired {
+			fmt.Printf("Removing expired outside collaborator %s:\n", login)
+			for _, change := range cfg.RemoveMember(login, nil) {
+				fmt.Printf("  %s\n", change)
+			}
+			delete(cfg.Collaborators, login)
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+		fmt.Printf("Review the changes and run 'push' to apply them into GitHub\n")
+
+		return nil
+	},
+}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/cilium/team-manager/pkg/config"
 	"github.com/cilium/team-manager/pkg/github"
-	"github.com/cilium/team-manager/pkg/persistence"
 	"github.com/cilium/team-manager/pkg/team"
 )
 
@@ -32,47 +31,64 @@ var diffCmd = &cobra.Command{
 	Short: "Display a diff between the local and remote configuration",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		var changed bool
+		err := forEachOrganization(func() error {
+			diff, err := runDiff(cmd)
+			if diff != "" {
+				fmt.Printf("%s", diff)
+				changed = true
+			}
+			return err
+		})
 		if err != nil {
-			return fmt.Errorf("failed to load local state: %w", err)
+			return err
 		}
-
-		if err = config.SanityCheck(cfg); err != nil {
-			return fmt.Errorf("failed to perform sanity check: %w", err)
-		}
-		config.SortConfig(cfg)
-		cfg.Normalize(opts)
-
-		ghClient, err := github.NewClientFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github client: %w", err)
-		}
-
-		ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github graphql client: %w", err)
-		}
-
-		if (orgName != "" && orgName != cfg.Organization) ||
-			(cfg.Organization != "" && orgName != cfg.Organization) {
-			return fmt.Errorf("Organization name different than the one in the configfile. %q != %q\n", orgName, cfg.Organization)
-		}
-
-		tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
-		if err != nil {
-			return fmt.Errorf("unable to initialize manager %w", err)
-		}
-
-		diff, err := tm.Diff(cmd.Context(), cfg, opts)
-		if err != nil {
-			return fmt.Errorf("failed to sync teams to GitHub: %w", err)
-		}
-
-		if diff != "" {
-			fmt.Printf("%s", diff)
+		if changed {
 			os.Exit(1)
 		}
-
 		return nil
 	},
 }
+
+// runDiff returns the diff between the local configuration of the current
+// organization and GitHub.
+func runDiff(cmd *cobra.Command) (string, error) {
+	cfg, err := loadState()
+	if err != nil {
+		return "", fmt.Errorf("failed to load local state: %w", err)
+	}
+
+	if err = config.SanityCheck(cfg); err != nil {
+		return "", fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+	if err := config.SortConfig(cfg); err != nil {
+		return "", err
+	}
+	cfg.Normalize(opts)
+
+	ghClient, err := github.NewClientFromEnv()
+	if err != nil {
+		return "", fmt.Errorf("failed to create github client: %w", err)
+	}
+
+	ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+	if err != nil {
+		return "", fmt.Errorf("failed to create github graphql client: %w", err)
+	}
+
+	if (orgName != "" && orgName != cfg.Organization) ||
+		(cfg.Organization != "" && orgName != cfg.Organization) {
+		return "", fmt.Errorf("Organization name different than the one in the configfile. %q != %q\n", orgName, cfg.Organization)
+	}
+
+	tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+	if err != nil {
+		return "", fmt.Errorf("unable to initialize manager %w", err)
+	}
+
+	diff, err := tm.Diff(cmd.Context(), cfg, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to sync teams to GitHub: %w", err)
+	}
+	return diff, nil
+}
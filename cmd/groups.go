@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/groups"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+var syncGroupsTeams []string
+
+func init() {
+	rootCmd.AddCommand(syncGroupsCmd)
+
+	syncGroupsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the changes to the members of the teams, without storing them")
+	syncGroupsCmd.Flags().StringSliceVar(&syncGroupsTeams, "teams", nil, "Only synchronize these teams (default all teams with a source)")
+}
+
+var syncGroupsCmd = &cobra.Command{
+	Use:   "sync-groups",
+	Short: "Set the members of the teams with a source from their external groups in local configuration",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		teamNames := syncGroupsTeams
+		if len(teamNames) == 0 {
+			for teamName, team := range cfg.AllTeams {
+				if team.Source != nil {
+					teamNames = append(teamNames, teamName)
+				}
+			}
+			sort.Strings(teamNames)
+		}
+		if len(teamNames) == 0 {
+			fmt.Printf("No teams with a source found\n")
+			return nil
+		}
+
+		now := time.Now()
+		var changed bool
+		for _, teamName := range teamNames {
+			team, ok := cfg.AllTeams[teamName]
+			if !ok {
+				return fmt.Errorf("team %q not found", teamName)
+			}
+			if team.Source == nil {
+				return fmt.Errorf("team %q does not have a source", teamName)
+			}
+
+			identities, unresolvable, err := groups.Members(cmd.Context(), team.Source, filepath.Dir(configFilename))
+			if err != nil {
+				return fmt.Errorf("failed to read members of team %q from %s: %w", teamName, team.Source, err)
+			}
+			for _, member := range unresolvable {
+				fmt.Printf("[WARN] member %s of team %q can't be identified\n", member, teamName)
+			}
+			members, unmapped := cfg.ResolveLogins(identities)
+			for _, identity := range unmapped {
+				fmt.Printf("[WARN] %s of team %q is not mapped to any member of the organization\n", identity, teamName)
+			}
+			if people := len(identities) + len(unresolvable); len(members) == 0 && people != 0 {
+				fmt.Printf("[WARN] none of the %d people of team %q are mapped to members of the organization, keeping its members\n", people, teamName)
+				continue
+			}
+
+			for _, login := range members {
+				if !slices.Contains(team.Members, login) {
+					fmt.Printf("Team %q: adding %s\n", teamName, login)
+					changed = true
+				}
+			}
+			for _, login := range team.Members {
+				if !slices.Contains(members, login) {
+					fmt.Printf("Team %q: removing %s\n", teamName, login)
+					changed = true
+				}
+			}
+
+			team.Members = members
+			notMember := func(login string) bool { return !slices.Contains(members, login) }
+			team.Maintainers = slices.DeleteFunc(team.Maintainers, notMember)
+			team.Mentors = slices.DeleteFunc(team.Mentors, notMember)
+			maps.DeleteFunc(team.MembersUntil, func(login string, _ config.Date) bool { return notMember(login) })
+
+			// The expiry of the kept members still applies, even though their
+			// group lists them.
+			for _, login := range members {
+				if until := team.MembersUntil[login]; until.Expired(now) {
+					fmt.Printf("[WARN] membership of %s in team %q expired on %s, remove it from the source or extend its expiry to keep it\n", login, teamName, until)
+				}
+			}
+		}
+
+		if !changed {
+			fmt.Printf("The members of all teams are up to date\n")
+			return nil
+		}
+		if dryRun {
+			return nil
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+		fmt.Printf("Review the changes and run 'push' to apply them into GitHub\n")
+
+		return nil
+	},
+}
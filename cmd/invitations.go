@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/team"
+	"github.com/cilium/team-manager/pkg/terminal"
+)
+
+var (
+	invitationsOlderThan time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(invitationsCmd)
+	invitationsCmd.AddCommand(listInvitationsCmd)
+	invitationsCmd.AddCommand(resendInvitationsCmd)
+	invitationsCmd.AddCommand(cancelInvitationsCmd)
+
+	invitationsCmd.PersistentFlags().DurationVar(&invitationsOlderThan, "older-than", 0, "Only consider invitations sent longer than this duration ago, e.g. 72h")
+	for _, cmd := range []*cobra.Command{resendInvitationsCmd, cancelInvitationsCmd} {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Dry run the steps without performing any write operation to GitHub")
+		cmd.Flags().BoolVar(&force, "force", false, "Apply the changes into GitHub without asking for confirmation")
+	}
+}
+
+var invitationsCmd = &cobra.Command{
+	Use:   "invitations",
+	Short: "Manage the invitations to join the organization that were not accepted yet",
+}
+
+var listInvitationsCmd = &cobra.Command{
+	Use:   "list [LOGIN ...]",
+	Short: "List pending, failed and expired invitations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, invitations, err := selectInvitations(cmd, args, true)
+		if err != nil {
+			return err
+		}
+		if len(invitations) == 0 {
+			fmt.Printf("No invitations found\n")
+			return nil
+		}
+		now := time.Now()
+		for _, invitation := range invitations {
+			status := "pending"
+			if invitation.Failed() {
+				status = "failed"
+			}
+			fmt.Printf("%-30s %-8s %-14s %6s old, %s\n", invitation.Invitee(), status, invitation.Role, invitation.Age(now).Round(time.Hour), invitation)
+		}
+		return nil
+	},
+}
+
+var resendInvitationsCmd = &cobra.Command{
+	Use:   "resend [LOGIN ...]",
+	Short: "Re-send stale invitations, cancelling them first if they are still pending",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && invitationsOlderThan == 0 {
+			return fmt.Errorf("either the logins of the invitations or --older-than must be specified")
+		}
+		tm, invitations, err := selectInvitations(cmd, args, true)
+		if err != nil {
+			return err
+		}
+		// Only re-send the most recent invitation of each user.
+		latest := map[string]config.Invitation{}
+		var selected []config.Invitation
+		for _, invitation := range invitations {
+			latest[invitation.Invitee()] = invitation
+		}
+		for _, invitation := range invitations {
+			if latest[invitation.Invitee()] == invitation {
+				selected = append(selected, invitation)
+			}
+		}
+		return applyInvitations(selected, "Re-sending", func(invitation config.Invitation) error {
+			return tm.ResendInvitation(cmd.Context(), invitation)
+		})
+	},
+}
+
+var cancelInvitationsCmd = &cobra.Command{
+	Use:   "cancel [LOGIN ...]",
+	Short: "Cancel stale pending invitations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && invitationsOlderThan == 0 {
+			return fmt.Errorf("either the logins of the invitations or --older-than must be specified")
+		}
+		tm, invitations, err := selectInvitations(cmd, args, false)
+		if err != nil {
+			return err
+		}
+		return applyInvitations(invitations, "Cancelling", func(invitation config.Invitation) error {
+			return tm.CancelInvitation(cmd.Context(), invitation)
+		})
+	},
+}
+
+// selectInvitations returns the invitations of the given logins, or of
+// everyone if no logins are given, that are older than --older-than. Failed
+// invitations are only returned if includeFailed is true.
+func selectInvitations(cmd *cobra.Command, logins []string, includeFailed bool) (*team.Manager, []config.Invitation, error) {
+	ghClient, err := github.NewClientFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create github client: %w", err)
+	}
+
+	ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create github graphql client: %w", err)
+	}
+
+	tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to initialize manager %w", err)
+	}
+
+	invitations, err := tm.ListInvitations(cmd.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wanted := map[string]struct{}{}
+	for _, login := range logins {
+		wanted[login] = struct{}{}
+	}
+	now := time.Now()
+	var selected []config.Invitation
+	for _, invitation := range invitations {
+		if invitation.Failed() && !includeFailed {
+			continue
+		}
+		if invitation.Age(now) < invitationsOlderThan {
+			continue
+		}
+		if _, ok := wanted[invitation.Invitee()]; len(wanted) != 0 && !ok {
+			continue
+		}
+		selected = append(selected, invitation)
+	}
+	return tm, selected, nil
+}
+
+// applyInvitations asks for confirmation and then calls apply for each of the
+// given invitations.
+func applyInvitations(invitations []config.Invitation, action string, apply func(config.Invitation) error) error {
+	if len(invitations) == 0 {
+		fmt.Printf("No invitations found\n")
+		return nil
+	}
+
+	fmt.Printf("%s the following invitations:\n", action)
+	for _, invitation := range invitations {
+		fmt.Printf("  %s: %s\n", invitation.Invitee(), invitation)
+	}
+
+	if dryRun {
+		fmt.Printf("Skipping confirmation due to dry run. No changes will be made into GitHub\n")
+		return nil
+	}
+	if !force {
+		yes, err := terminal.AskForConfirmation("Continue?")
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return nil
+		}
+	}
+
+	for _, invitation := range invitations {
+		if err := apply(invitation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,16 +4,37 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 
 	"github.com/cilium/team-manager/pkg/config"
 	"github.com/cilium/team-manager/pkg/persistence"
+	"github.com/cilium/team-manager/pkg/policy"
+)
+
+var (
+	maxOwners         int
+	expiryWarningDays int
+	lintOutput        string
+	lintCheck         bool
 )
 
 func init() {
 	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().IntVar(&maxOwners, "max-owners", 0, "Fail if the organization has more owners than this number (0 disables the check)")
+	checkCmd.Flags().IntVar(&expiryWarningDays, "expiry-warning-days", 14, "Warn about team memberships that expire within this number of days")
+	checkCmd.Flags().BoolVar(&lintCheck, "check", false, "Only check that the config and override files are formatted, printing the formatting changes, without rewriting them")
+	checkCmd.Flags().StringVarP(&lintOutput, "output", "o", "human", "Output format of the problems found, one of: human, json, github")
 }
 
 var checkCmd = &cobra.Command{
@@ -21,21 +42,171 @@ var checkCmd = &cobra.Command{
 	Short: "Checks and formats local config",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, _ []string) error {
+		switch lintOutput {
+		case "human", "github":
+		case "json":
+			if organizationsFilename != "" {
+				return fmt.Errorf("--output json can't be used with --organizations-filename")
+			}
+		default:
+			return fmt.Errorf("unknown output format %q", lintOutput)
+		}
 
-		localCfg, err := persistence.LoadState(configFilename, overrideFilename)
-		if err != nil {
-			return fmt.Errorf("failed to load local state: %w", err)
+		return forEachOrganization(runLint)
+	},
+}
+
+// runLint checks and formats the local configuration of the current
+// organization.
+func runLint() error {
+	localCfg, err := loadState()
+	if err != nil {
+		return fmt.Errorf("failed to load local state: %w", err)
+	}
+
+	findings := config.Validate(localCfg)
+	findings = append(findings, config.ValidateCollaborators(localCfg)...)
+	findings = append(findings, config.ValidatePeople(localCfg)...)
+	if err := persistence.LocateFindings(configFilename, findings); err != nil {
+		return fmt.Errorf("failed to locate problems in %s: %w", configFilename, err)
+	}
+	if err := printFindings(findings); err != nil {
+		return err
+	}
+	if len(findings) != 0 {
+		return fmt.Errorf("%d problem(s) found in %s", len(findings), configFilename)
+	}
+
+	if maxOwners > 0 {
+		if err := config.CheckMaxOwners(localCfg, maxOwners); err != nil {
+			return err
 		}
+	}
+
+	if err := checkPolicy(localCfg); err != nil {
+		return err
+	}
+
+	warnExpiringMemberships(localCfg, expiryWarningDays)
+
+	if lintCheck {
+		return checkFormatting(localCfg)
+	}
+
+	if err = persistence.StoreState(configFilename, localCfg); err != nil {
+		return fmt.Errorf("failed to store state to config: %w", err)
+	}
+
+	return nil
+}
 
-		err = config.SanityCheck(localCfg)
+// checkFormatting prints a unified diff between the config and override files
+// and their canonical form, computed from cfg as loaded by loadState, and
+// fails if they differ. Only the config file is formatted by lint, so the
+// changes to the override file have to be applied by hand.
+func checkFormatting(cfg *config.Config) error {
+	current, canonical, err := persistence.CanonicalFiles(configFilename, overrideFilename, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to format local state: %w", err)
+	}
+
+	var errs []error
+	for _, file := range []string{configFilename, overrideFilename} {
+		if file == "" || bytes.Equal(current[file], canonical[file]) {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current[file])),
+			B:        difflib.SplitLines(string(canonical[file])),
+			FromFile: file,
+			ToFile:   file + " (formatted)",
+			Context:  3,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to perform sanity check: %w", err)
+			return err
+		}
+		fmt.Print(diff)
+		if file == configFilename {
+			errs = append(errs, fmt.Errorf("%s not formatted, run 'lint' without --check to format", file))
+		} else {
+			errs = append(errs, fmt.Errorf("%s not formatted, apply the changes above to format", file))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// printFindings prints the problems found in the configuration file in the
+// format selected with --output. GitHub Actions annotations make them appear
+// inline on pull requests.
+func printFindings(findings []config.Finding) error {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	switch lintOutput {
+	case "json":
+		if findings == nil {
+			findings = []config.Finding{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	case "github":
+		for _, f := range findings {
+			fmt.Printf("::error file=%s,line=%d,col=%d,title=%s::%s\n",
+				escapeGitHubProperty(f.File), f.Line, f.Column, escapeGitHubProperty(f.Rule), escapeGitHubData(f.Message))
 		}
+	default:
+		for _, f := range findings {
+			fmt.Fprintf(os.Stderr, "%s\n", f)
+		}
+	}
+	return nil
+}
+
+// escapeGitHubData escapes the message of a GitHub Actions workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
 
-		if err = persistence.StoreState(configFilename, localCfg); err != nil {
-			return fmt.Errorf("failed to store state to config: %w", err)
+// escapeGitHubProperty escapes a property of a GitHub Actions workflow
+// command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// warnExpiringMemberships prints a warning for every team membership that
+// expired, or expires within the given number of days.
+func warnExpiringMemberships(cfg *config.Config, days int) {
+	now := time.Now()
+	for _, expiry := range config.ExpiringMemberships(cfg, now.AddDate(0, 0, days)) {
+		if expiry.Until.Expired(now) {
+			fmt.Fprintf(os.Stderr, "Warning: %s, it expired and will be removed on the next push\n", expiry)
+			continue
 		}
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", expiry)
+	}
+}
 
+// checkPolicy evaluates the rules of the policy file, if any, against the
+// given configuration. It prints all findings and fails if any of them is an
+// error.
+func checkPolicy(cfg *config.Config) error {
+	if policyFilename == "" {
 		return nil
-	},
+	}
+	p, err := policy.Load(policyFilename)
+	if err != nil {
+		return err
+	}
+	findings := policy.Evaluate(p, cfg)
+	for _, finding := range findings {
+		fmt.Fprintf(os.Stderr, "%s\n", finding)
+	}
+	if errs := policy.Errors(findings); errs != 0 {
+		return fmt.Errorf("%d policy violation(s) found", errs)
+	}
+	return nil
 }
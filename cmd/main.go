@@ -26,6 +26,7 @@ var (
 	orgName          string
 	configFilename   string
 	overrideFilename string
+	policyFilename   string
 )
 
 func init() {
@@ -34,6 +35,7 @@ func init() {
 	flag.StringVar(&orgName, "org", "cilium", "GitHub organization name")
 	flag.StringVar(&configFilename, "config-filename", "team-assignments.yaml", "Config filename")
 	flag.StringVar(&overrideFilename, "override-filename", "", "Team Override filename")
+	flag.StringVar(&policyFilename, "policy-filename", "", "Policy filename with the organization rules evaluated by 'lint' and 'push'")
 }
 
 var rootCmd = &cobra.Command{
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/migration"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the migration steps that would be applied")
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the config file to the current version of the file format",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		data, err := os.ReadFile(configFilename)
+		if err != nil {
+			return err
+		}
+		version, err := migration.Version(configFilename, data)
+		if err != nil {
+			return err
+		}
+		_, steps, err := migration.Migrate(configFilename, data)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			fmt.Printf("%s is already at version %d\n", configFilename, version)
+			return nil
+		}
+		for _, step := range steps {
+			fmt.Printf("Migrating from %s\n", step)
+		}
+		if dryRun {
+			fmt.Printf("Skipping migration due to dry run. %s was not modified\n", configFilename)
+			return nil
+		}
+
+		// LoadState applies the migration steps in memory.
+		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+		fmt.Printf("Migrated %s from version %d to %d\n", configFilename, version, config.CurrentVersion)
+		return nil
+	},
+}
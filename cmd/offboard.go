@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+var (
+	offboardKeepRepos []string
+	offboardReason    string
+	offboardSponsor   string
+)
+
+func init() {
+	rootCmd.AddCommand(offboardCmd)
+
+	offboardCmd.Flags().StringSliceVar(&offboardKeepRepos, "keep-repos", []string{}, "Keep the direct permissions of the user on these repositories, converting the user to an outside collaborator")
+	offboardCmd.Flags().StringVar(&offboardReason, "reason", "", "Reason why the user is kept as an outside collaborator, required with --keep-repos")
+	offboardCmd.Flags().StringVar(&offboardSponsor, "sponsor", "", "Member accountable for the user as an outside collaborator, required with --keep-repos")
+}
+
+var offboardCmd = &cobra.Command{
+	Use:   "offboard USER",
+	Short: "Remove a user from the organization, all teams and repositories in local configuration",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(offboardKeepRepos) != 0 && (offboardReason == "" || offboardSponsor == "") {
+			return fmt.Errorf("--reason and --sponsor are required to keep the user as an outside collaborator")
+		}
+
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		login, err := findUser(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		if cfg.Protected.IsMember(login) {
+			return fmt.Errorf("member %q is protected and can't be offboarded, remove it from the protected members first", login)
+		}
+
+		if len(offboardKeepRepos) != 0 {
+			if offboardSponsor, err = findUser(cfg, offboardSponsor); err != nil {
+				return fmt.Errorf("unable to find sponsor: %w", err)
+			}
+			if offboardSponsor == login {
+				return fmt.Errorf("member %q can't sponsor itself", login)
+			}
+		}
+
+		var keepRepos []config.RepositoryName
+		userRepos := cfg.UserRepositories(login)
+		for _, repo := range offboardKeepRepos {
+			if !slices.Contains(userRepos, config.RepositoryName(repo)) {
+				return fmt.Errorf("member %q doesn't have a direct permission on repository %q", login, repo)
+			}
+			keepRepos = append(keepRepos, config.RepositoryName(repo))
+		}
+
+		var inOverrides bool
+		for _, override := range cfg.TeamOverrides {
+			if slices.Contains(override.Members, login) || slices.Contains(override.Mentors, login) {
+				inOverrides = true
+			}
+		}
+
+		changes := cfg.RemoveMember(login, keepRepos)
+		if len(keepRepos) != 0 {
+			if cfg.Collaborators == nil {
+				cfg.Collaborators = map[string]config.OutsideCollaborator{}
+			}
+			cfg.Collaborators[login] = config.OutsideCollaborator{Reason: offboardReason, Sponsor: offboardSponsor}
+			changes = append(changes, fmt.Sprintf("converted to outside collaborator sponsored by %s: %s", offboardSponsor, offboardReason))
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+		if inOverrides {
+			if err = persistence.StoreOverrides(overrideFilename, cfg); err != nil {
+				return fmt.Errorf("failed to store overrides: %w", err)
+			}
+		}
+
+		fmt.Printf("Offboarded %s:\n", login)
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		if id, ok := cfg.AllPeople().Owner(login); ok {
+			person := cfg.AllPeople()[id]
+			for _, account := range append(slices.Clone(person.Logins), person.Bots...) {
+				if account == login {
+					continue
+				}
+				_, isMember := cfg.Members[account]
+				_, isCollaborator := cfg.Collaborators[account]
+				if isMember || isCollaborator {
+					fmt.Printf("[WARN] %q is also an account of %s, offboard it separately if needed\n", account, id)
+				}
+			}
+		}
+		fmt.Printf("Review the changes and run 'push' to apply them into GitHub\n")
+
+		return nil
+	},
+}
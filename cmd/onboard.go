@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+var (
+	onboardProfile string
+	onboardTeams   []string
+)
+
+func init() {
+	rootCmd.AddCommand(onboardCmd)
+
+	onboardCmd.Flags().StringVar(&onboardProfile, "profile", "", "Name of the profile, from the 'profiles' of the local configuration, to apply to the user")
+	onboardCmd.Flags().StringSliceVar(&onboardTeams, "teams", []string{}, "Add the user to these teams, in addition to the teams of the profile")
+}
+
+var onboardCmd = &cobra.Command{
+	Use:   "onboard USER",
+	Short: "Add a user to the organization in local configuration, with the teams and permissions of a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if onboardProfile == "" && len(onboardTeams) == 0 {
+			return fmt.Errorf("either --profile or --teams must be specified")
+		}
+
+		ghClient, err := github.NewClientFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github client: %w", err)
+		}
+
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		for login := range cfg.Members {
+			if strings.EqualFold(login, args[0]) {
+				return fmt.Errorf("user %q already belongs to the organization", login)
+			}
+		}
+
+		if err = addUsersToConfig(cmd.Context(), args, cfg, ghClient); err != nil {
+			return fmt.Errorf("failed to add user: %w", err)
+		}
+		// The login is stored as returned by GitHub, which might differ in
+		// case from the one given.
+		var login string
+		for member := range cfg.Members {
+			if strings.EqualFold(member, args[0]) {
+				login = member
+			}
+		}
+
+		changes, err := cfg.Onboard(login, onboardProfile, onboardTeams, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to onboard user %q: %w", login, err)
+		}
+
+		// Nothing is stored if any of the changes fails, so the whole
+		// onboarding is a single edit of the configuration file.
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+
+		fmt.Printf("Onboarded %s:\n", login)
+		fmt.Printf("  added to organization members\n")
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		fmt.Printf("Review the changes and run 'push' to apply them into GitHub\n")
+
+		return nil
+	},
+}
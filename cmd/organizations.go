@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/persistence"
+)
+
+var (
+	organizationsFilename string
+
+	// people is the registry of people shared by all organizations, set by
+	// forEachOrganization.
+	people config.People
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&organizationsFilename, "organizations-filename", "",
+		"Organizations filename with the configuration files of several organizations and the people shared by them. Overrides --org, --config-filename and --override-filename in 'diff', 'lint', 'push' and 'status'")
+}
+
+// forEachOrganization runs fn once for each organization of the organizations
+// file, with orgName, configFilename and overrideFilename set to the ones of
+// the organization. Without an organizations file, fn runs once with the
+// global flags. The errors of all organizations are returned together.
+func forEachOrganization(fn func() error) error {
+	if organizationsFilename == "" {
+		return fn()
+	}
+
+	orgs, err := persistence.LoadOrganizations(organizationsFilename)
+	if err != nil {
+		return fmt.Errorf("failed to load organizations: %w", err)
+	}
+
+	prevOrgName, prevConfigFilename, prevOverrideFilename := orgName, configFilename, overrideFilename
+	defer func() {
+		orgName, configFilename, overrideFilename = prevOrgName, prevConfigFilename, prevOverrideFilename
+		people = nil
+	}()
+
+	people = orgs.People
+	var errs []error
+	for _, name := range orgs.Names() {
+		files := orgs.Organizations[name]
+		orgName, configFilename, overrideFilename = name, files.ConfigFilename, files.OverrideFilename
+
+		fmt.Printf("==> Organization %q\n", name)
+		if err := fn(); err != nil {
+			errs = append(errs, fmt.Errorf("organization %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadState loads the local state of the current organization, with the
+// people shared with the other organizations and the names and Slack IDs of
+// its members set from the people they belong to.
+func loadState() (*config.Config, error) {
+	cfg, err := persistence.LoadState(configFilename, overrideFilename)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SharedPeople = people
+	cfg.ApplyPeople()
+	return cfg, nil
+}
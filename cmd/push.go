@@ -4,12 +4,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cilium/team-manager/pkg/config"
 	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/journal"
 	"github.com/cilium/team-manager/pkg/persistence"
 	"github.com/cilium/team-manager/pkg/team"
 )
@@ -20,6 +22,11 @@ var (
 	pushRepos   bool
 	pushMembers bool
 	pushTeams   bool
+	resume      bool
+
+	continueOnError bool
+
+	journalFilename string
 )
 
 func init() {
@@ -30,6 +37,9 @@ func init() {
 	pushCmd.Flags().BoolVar(&pushRepos, "repositories", true, "Push repositories permissions configuration into GitHub")
 	pushCmd.Flags().BoolVar(&pushMembers, "members", true, "Push members association to the organization into GitHub")
 	pushCmd.Flags().BoolVar(&pushTeams, "teams", true, "Push teams organization to the organization into GitHub")
+	pushCmd.Flags().BoolVar(&resume, "resume", false, "Resume the previous push that did not finish, with the same options it was started with, skipping the operations it applied")
+	pushCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Apply all repository permissions possible and report the ones that failed at the end, instead of stopping on the first failure")
+	pushCmd.Flags().StringVar(&journalFilename, "journal-filename", "", "Journal filename where every applied operation is recorded (default \"<config-filename>.journal\")")
 }
 
 var pushCmd = &cobra.Command{
@@ -37,46 +47,141 @@ var pushCmd = &cobra.Command{
 	Short: "Update team assignments in GitHub from local files",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
-		if err != nil {
-			return fmt.Errorf("failed to load local state: %w", err)
+		if organizationsFilename != "" && journalFilename != "" {
+			return fmt.Errorf("--journal-filename can't be used with --organizations-filename, each organization uses the journal next to its configuration file")
 		}
+		return forEachOrganization(func() error {
+			return runPush(cmd)
+		})
+	},
+}
 
-		if err = config.SanityCheck(cfg); err != nil {
-			return fmt.Errorf("failed to perform sanity check: %w", err)
+// runPush pushes the local configuration of the current organization into
+// GitHub.
+func runPush(cmd *cobra.Command) error {
+	cfg, err := loadState()
+	if err != nil {
+		return fmt.Errorf("failed to load local state: %w", err)
+	}
+
+	if err = config.SanityCheck(cfg); err != nil {
+		return fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+	if err := config.SortConfig(cfg); err != nil {
+		return err
+	}
+
+	if err := checkPolicy(cfg); err != nil {
+		return err
+	}
+
+	ghClient, err := github.NewClientFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create github client: %w", err)
+	}
+
+	ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create github graphql client: %w", err)
+	}
+
+	if (orgName != "" && orgName != cfg.Organization) ||
+		(cfg.Organization != "" && orgName != cfg.Organization) {
+		return fmt.Errorf("Organization name different than the one in the configfile. %q != %q\n", orgName, cfg.Organization)
+	}
+
+	tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+	if err != nil {
+		return fmt.Errorf("unable to initialize manager %w", err)
+	}
+
+	j, err := journal.Open(journalPath())
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	opts := journal.Options{
+		Repositories: pushRepos,
+		Members:      pushMembers,
+		Teams:        pushTeams,
+	}
+	// --resume continues the most recent unfinished run, the other ones are
+	// only reported.
+	var unfinished *journal.Run
+	runs := j.Unfinished()
+	if len(runs) != 0 {
+		unfinished = runs[len(runs)-1]
+	}
+	switch {
+	case resume && unfinished == nil:
+		return fmt.Errorf("no unfinished push found in journal %q", journalPath())
+	case resume && unfinished.Options.RollbackOf != "":
+		return fmt.Errorf("run %q is an unfinished rollback of push %q, use 'rollback --run %s' to continue it",
+			unfinished.ID, unfinished.Options.RollbackOf, unfinished.Options.RollbackOf)
+	case resume:
+		fmt.Printf("Resuming push %q after %d applied operations\n", unfinished.ID, len(unfinished.Operations))
+		opts = unfinished.Options
+		tm.SkipApplied(unfinished.Operations)
+		runs = runs[:len(runs)-1]
+	}
+	for i, run := range runs {
+		switch {
+		case run.Options.RollbackOf != "":
+			fmt.Printf("[WARN] rollback %q of push %q did not finish after %d applied operations. Use 'rollback --run %s' to continue it.\n",
+				run.ID, run.Options.RollbackOf, len(run.Operations), run.Options.RollbackOf)
+		case !resume && i == len(runs)-1:
+			fmt.Printf("[WARN] previous push %q did not finish after %d applied operations. Use --resume to continue it.\n",
+				run.ID, len(run.Operations))
+		default:
+			fmt.Printf("[WARN] previous push %q did not finish after %d applied operations. Only the most recent unfinished push can be continued with --resume.\n",
+				run.ID, len(run.Operations))
 		}
-		config.SortConfig(cfg)
+	}
 
-		ghClient, err := github.NewClientFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github client: %w", err)
+	if !dryRun {
+		if resume {
+			j.Resume(unfinished)
+		} else if _, err := j.Start(opts); err != nil {
+			return fmt.Errorf("failed to start journal run: %w", err)
 		}
-
-		ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github graphql client: %w", err)
+		tm.SetJournal(j, func() error {
+			return persistence.StoreState(configFilename, cfg)
+		})
+	}
+
+	tm.ContinueOnError = continueOnError
+	newCfg, err := tm.PushConfiguration(cmd.Context(), cfg, force, dryRun, opts.Repositories, opts.Members, opts.Teams)
+	if err != nil {
+		var permErrs team.PermissionErrors
+		if errors.As(err, &permErrs) {
+			fmt.Printf("The following repository permission changes were not applied:\n")
+			for _, permErr := range permErrs {
+				fmt.Printf("  - %s\n", permErr)
+			}
+			return fmt.Errorf("failed to sync %d repository permission(s) to GitHub", len(permErrs))
 		}
+		return fmt.Errorf("failed to sync teams to GitHub: %w", err)
+	}
 
-		if (orgName != "" && orgName != cfg.Organization) ||
-			(cfg.Organization != "" && orgName != cfg.Organization) {
-			return fmt.Errorf("Organization name different than the one in the configfile. %q != %q\n", orgName, cfg.Organization)
-		}
+	err = persistence.StoreState(configFilename, newCfg)
+	if err != nil {
+		return fmt.Errorf("failed to store local state: %w", err)
+	}
 
-		tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
-		if err != nil {
-			return fmt.Errorf("unable to initialize manager %w", err)
+	if !dryRun {
+		if err := j.Finish(); err != nil {
+			return fmt.Errorf("failed to finish journal run: %w", err)
 		}
+	}
 
-		newCfg, err := tm.PushConfiguration(cmd.Context(), cfg, force, dryRun, pushRepos, pushMembers, pushTeams)
-		if err != nil {
-			return fmt.Errorf("failed to sync teams to GitHub: %w", err)
-		}
-
-		err = persistence.StoreState(configFilename, newCfg)
-		if err != nil {
-			return fmt.Errorf("failed to store local state: %w", err)
-		}
+	return nil
+}
 
-		return nil
-	},
+// journalPath returns the path of the journal of the given configuration
+// file.
+func journalPath() string {
+	if journalFilename != "" {
+		return journalFilename
+	}
+	return configFilename + ".journal"
 }
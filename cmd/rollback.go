@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/journal"
+	"github.com/cilium/team-manager/pkg/team"
+)
+
+var (
+	rollbackRun string
+)
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().StringVar(&rollbackRun, "run", "", "ID of the push to revert (default to the most recent one not reverted yet)")
+	rollbackCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Dry run the steps without performing any write operation to GitHub")
+	rollbackCmd.Flags().BoolVar(&force, "force", false, "Revert the push into GitHub without asking for confirmation, even if it was already reverted")
+	rollbackCmd.Flags().StringVar(&journalFilename, "journal-filename", "", "Journal filename where every applied operation is recorded (default \"<config-filename>.journal\")")
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Revert in GitHub the operations applied by a previous push",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		j, err := journal.Open(journalPath())
+		if err != nil {
+			return fmt.Errorf("failed to open journal: %w", err)
+		}
+
+		var run *journal.Run
+		if rollbackRun != "" {
+			run, err = j.Run(rollbackRun)
+			if err != nil {
+				return err
+			}
+			if j.RolledBack(run.ID) && !force {
+				return fmt.Errorf("run %q was already reverted, use --force to revert it again", run.ID)
+			}
+		} else {
+			// Default to the most recent push that wasn't reverted yet,
+			// skipping the rollbacks so that running rollback twice neither
+			// reverts the same push twice nor the first rollback.
+			runs := j.Runs()
+			for i := len(runs) - 1; i >= 0 && run == nil; i-- {
+				if runs[i].Options.RollbackOf == "" && !j.RolledBack(runs[i].ID) {
+					run = runs[i]
+				}
+			}
+			if run == nil {
+				return fmt.Errorf("no push left to revert in journal %q", journalPath())
+			}
+		}
+		fmt.Printf("Reverting push %q with %d applied operations\n", run.ID, len(run.Operations))
+
+		ghClient, err := github.NewClientFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github client: %w", err)
+		}
+
+		ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github graphql client: %w", err)
+		}
+
+		tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+		if err != nil {
+			return fmt.Errorf("unable to initialize manager %w", err)
+		}
+
+		// Teams are referred by the slugs stored in the local configuration,
+		// if any, since they can't be derived from the team names.
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+		tm.UseTeamSlugs(cfg)
+
+		steps, irreversible := team.PlanRollback(run)
+
+		// An unfinished rollback of the same push is continued, skipping the
+		// operations it already applied.
+		var unfinished *journal.Run
+		if rollbacks := j.Rollbacks(run.ID); len(rollbacks) != 0 && !rollbacks[len(rollbacks)-1].Finished {
+			unfinished = rollbacks[len(rollbacks)-1]
+			fmt.Printf("Resuming rollback %q after %d applied operations\n", unfinished.ID, len(unfinished.Operations))
+			steps, err = tm.RemainingRollbackSteps(steps, unfinished.Operations)
+			if err != nil {
+				return fmt.Errorf("failed to resume rollback %q: %w", unfinished.ID, err)
+			}
+			if len(steps) == 0 && !dryRun {
+				// It stopped right before being marked as finished.
+				j.Resume(unfinished)
+				if err := j.Finish(); err != nil {
+					return fmt.Errorf("failed to finish journal run: %w", err)
+				}
+			}
+		}
+
+		confirmed, err := team.ConfirmRollback(steps, irreversible, force, dryRun)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+
+		// The rollback is only recorded once confirmed, so that declining it
+		// doesn't leave an empty run in the journal.
+		if unfinished != nil {
+			j.Resume(unfinished)
+		} else if _, err := j.Start(journal.Options{RollbackOf: run.ID}); err != nil {
+			return fmt.Errorf("failed to start journal run: %w", err)
+		}
+		tm.SetJournal(j, nil)
+
+		if err := tm.Rollback(cmd.Context(), steps); err != nil {
+			return fmt.Errorf("failed to revert push %q: %w", run.ID, err)
+		}
+
+		if err := j.Finish(); err != nil {
+			return fmt.Errorf("failed to finish journal run: %w", err)
+		}
+		fmt.Printf("[INFO] GitHub no longer matches the local configuration. Restore %q to its state before push %q, or run 'sync' to update it.\n", configFilename, run.ID)
+
+		return nil
+	},
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/daemon"
+	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/journal"
+	"github.com/cilium/team-manager/pkg/persistence"
+	"github.com/cilium/team-manager/pkg/team"
+	"github.com/cilium/team-manager/pkg/webhook"
+)
+
+var (
+	serveInterval      time.Duration
+	serveMode          string
+	serveListenAddress string
+	serveWebhookPath   string
+	serveOpts          config.NormalizeOpts
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", 15*time.Minute, "Interval between reconciliations")
+	serveCmd.Flags().StringVar(&serveMode, "mode", string(daemon.ModeReport), "What to do when GitHub drifts from the local configuration, one of: report, enforce")
+	serveCmd.Flags().StringVar(&serveListenAddress, "listen-address", ":8080", "Address of the HTTP server with the status of the reconciliations")
+	serveCmd.Flags().StringVar(&serveWebhookPath, "webhook-path", "/webhook", "Path of the HTTP server receiving the GitHub webhooks, enabled when the WEBHOOK_SECRET environment variable is set")
+	serveCmd.Flags().BoolVar(&serveOpts.Repositories, "repositories", true, "Reconcile repositories permissions configuration")
+	serveCmd.Flags().BoolVar(&serveOpts.Members, "members", true, "Reconcile members association to the organization")
+	serveCmd.Flags().BoolVar(&serveOpts.Teams, "teams", true, "Reconcile teams organization")
+	serveCmd.Flags().StringVar(&journalFilename, "journal-filename", "", "Journal filename where every operation applied in enforce mode is recorded (default \"<config-filename>.journal\")")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Periodically compare the local configuration with GitHub, reporting or reverting any drift",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		mode, err := daemon.ParseMode(serveMode)
+		if err != nil {
+			return err
+		}
+		if serveInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		ghClient, err := github.NewClientFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github client: %w", err)
+		}
+
+		ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github graphql client: %w", err)
+		}
+
+		tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+		if err != nil {
+			return fmt.Errorf("unable to initialize manager %w", err)
+		}
+
+		d := daemon.New(func(ctx context.Context, enforce bool) (string, error) {
+			return reconcile(ctx, tm, enforce)
+		}, mode, serveInterval)
+
+		mux := http.NewServeMux()
+		mux.Handle("/", d.Handler())
+		if secret := os.Getenv("WEBHOOK_SECRET"); secret != "" {
+			mux.Handle(serveWebhookPath, &webhook.Handler{
+				Secret:       []byte(secret),
+				Organization: orgName,
+				OnEvent: func(e webhook.Event) {
+					d.Enqueue(e.String(), func(ctx context.Context) ([]string, error) {
+						return checkTarget(ctx, tm, e.Target)
+					})
+				},
+			})
+			fmt.Printf("Receiving GitHub webhooks on %s\n", serveWebhookPath)
+		}
+
+		srv := &http.Server{
+			Addr:              serveListenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		srvErr := make(chan error, 1)
+		go func() {
+			srvErr <- srv.ListenAndServe()
+		}()
+		fmt.Printf("Serving the status on %s, reconciling every %s in %s mode\n", serveListenAddress, serveInterval, mode)
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			d.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Shutting down after the reconciliation in progress\n")
+		case err = <-srvErr:
+			cancel()
+		}
+		<-done
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+			return fmt.Errorf("failed to shut down the HTTP server: %w", shutdownErr)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// reconcile compares the local configuration, read again on every call, with
+// GitHub and returns the drift between them. With enforce, the local
+// configuration is pushed into GitHub if they drifted.
+func reconcile(ctx context.Context, tm *team.Manager, enforce bool) (string, error) {
+	cfg, err := loadState()
+	if err != nil {
+		return "", fmt.Errorf("failed to load local state: %w", err)
+	}
+	if err = config.SanityCheck(cfg); err != nil {
+		return "", fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+	if cfg.Organization != "" && orgName != cfg.Organization {
+		return "", fmt.Errorf("Organization name different than the one in the configfile. %q != %q", orgName, cfg.Organization)
+	}
+	if err := config.SortConfig(cfg); err != nil {
+		return "", err
+	}
+	cfg.Normalize(serveOpts)
+
+	diff, err := tm.Diff(ctx, cfg, serveOpts)
+	if err != nil || diff == "" || !enforce {
+		return diff, err
+	}
+
+	// Normalize removed the local-only parts of the configuration, so it's
+	// loaded again to push it.
+	cfg, err = loadState()
+	if err != nil {
+		return diff, fmt.Errorf("failed to load local state: %w", err)
+	}
+	if err := config.SortConfig(cfg); err != nil {
+		return diff, err
+	}
+	if err := checkPolicy(cfg); err != nil {
+		return diff, err
+	}
+
+	// The operations are recorded like the ones of push, so that they are
+	// reported by push and can be reverted with rollback.
+	j, err := journal.Open(journalPath())
+	if err != nil {
+		return diff, fmt.Errorf("failed to open journal: %w", err)
+	}
+	if _, err := j.Start(journal.Options{
+		Repositories: serveOpts.Repositories,
+		Members:      serveOpts.Members,
+		Teams:        serveOpts.Teams,
+	}); err != nil {
+		return diff, fmt.Errorf("failed to start journal run: %w", err)
+	}
+	tm.SetJournal(j, func() error {
+		return persistence.StoreState(configFilename, cfg)
+	})
+	defer tm.SetJournal(nil, nil)
+
+	newCfg, err := tm.PushConfiguration(ctx, cfg, true, false, serveOpts.Repositories, serveOpts.Members, serveOpts.Teams)
+	if err != nil {
+		return diff, fmt.Errorf("failed to sync teams to GitHub: %w", err)
+	}
+	if err = persistence.StoreState(configFilename, newCfg); err != nil {
+		return diff, fmt.Errorf("failed to store local state: %w", err)
+	}
+	if err := j.Finish(); err != nil {
+		return diff, fmt.Errorf("failed to finish journal run: %w", err)
+	}
+	return diff, nil
+}
+
+// checkTarget compares the target of an event in the local configuration,
+// read again on every call, with GitHub and returns how it drifted.
+func checkTarget(ctx context.Context, tm *team.Manager, target team.Target) ([]string, error) {
+	cfg, err := loadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local state: %w", err)
+	}
+	if err = config.SanityCheck(cfg); err != nil {
+		return nil, fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+	if err := config.SortConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	// Only check the entities that are reconciled.
+	if !serveOpts.Teams {
+		target.Team, target.TeamSlug = "", ""
+	}
+	if !serveOpts.Members && target.Team == "" {
+		target.Member = ""
+	}
+	if !serveOpts.Repositories {
+		target.Repository = ""
+	}
+	if target.IsZero() {
+		return nil, nil
+	}
+	return tm.DiffTarget(ctx, cfg, target)
+}
@@ -4,7 +4,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/cilium/team-manager/pkg/github"
@@ -12,11 +11,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cilium/team-manager/pkg/config"
-	"github.com/cilium/team-manager/pkg/persistence"
 )
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().IntVar(&expiryWarningDays, "expiry-warning-days", 14, "Warn about team memberships that expire within this number of days")
 }
 
 var statusCmd = &cobra.Command{
@@ -24,36 +24,44 @@ var statusCmd = &cobra.Command{
 	Short: "Checks user status for all teams",
 	Args:  cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, _ []string) error {
-
-		localCfg, err := persistence.LoadState(configFilename, overrideFilename)
-		if err != nil {
-			return fmt.Errorf("failed to load local state: %w", err)
-		}
-
-		err = config.SanityCheck(localCfg)
-		if err != nil {
-			return fmt.Errorf("failed to perform sanity check: %w", err)
-		}
-
-		ghClient, err := github.NewClientFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github client: %w", err)
-		}
-
-		ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
-		if err != nil {
-			return fmt.Errorf("failed to create github graphql client: %w", err)
-		}
-		tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
-		if err != nil {
-			return fmt.Errorf("unable to initialize manager %w", err)
-		}
-
-		err = tm.CheckUserStatus(context.Background(), localCfg)
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return forEachOrganization(func() error {
+			return runStatus(cmd)
+		})
 	},
 }
+
+// runStatus checks the status of the members of the current organization.
+func runStatus(cmd *cobra.Command) error {
+	localCfg, err := loadState()
+	if err != nil {
+		return fmt.Errorf("failed to load local state: %w", err)
+	}
+
+	err = config.SanityCheck(localCfg)
+	if err != nil {
+		return fmt.Errorf("failed to perform sanity check: %w", err)
+	}
+
+	warnExpiringMemberships(localCfg, expiryWarningDays)
+
+	ghClient, err := github.NewClientFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create github client: %w", err)
+	}
+
+	ghGraphQLClient, err := github.NewClientGraphQLFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create github graphql client: %w", err)
+	}
+	tm, err := team.NewManager(ghClient, ghGraphQLClient, orgName)
+	if err != nil {
+		return fmt.Errorf("unable to initialize manager %w", err)
+	}
+
+	err = tm.CheckUserStatus(cmd.Context(), localCfg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
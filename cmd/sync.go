@@ -57,6 +57,15 @@ var syncCmd = &cobra.Command{
 			return fmt.Errorf("failed to sync teams to GitHub: %w", err)
 		}
 
+		// Members renamed in GitHub keep their teams, repositories and
+		// exclusions from the local configuration.
+		for oldLogin, newLogin := range config.LoginRenames(cfg, newCfg) {
+			fmt.Printf("[INFO] member %q was renamed to %q in GitHub, migrating its login\n", oldLogin, newLogin)
+			if err = migrateLogin(cfg, oldLogin, newLogin); err != nil {
+				return err
+			}
+		}
+
 		mergedCfg, err := cfg.Merge(newCfg)
 		if err != nil {
 			return fmt.Errorf("unable to merge upstream configuration to local config: %w", err)
@@ -20,6 +20,8 @@ func init() {
 	rootCmd.AddCommand(addTeamsCmd)
 	rootCmd.AddCommand(setTeamsUsersCmd)
 	rootCmd.AddCommand(setTeamsMentorsCmd)
+	rootCmd.AddCommand(setTeamsMaintainersCmd)
+	rootCmd.AddCommand(renameTeamCmd)
 }
 
 var addTeamsCmd = &cobra.Command{
@@ -32,7 +34,7 @@ var addTeamsCmd = &cobra.Command{
 			return fmt.Errorf("failed to create github client: %w", err)
 		}
 
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		cfg, err := loadState()
 		if err != nil {
 			return fmt.Errorf("failed to load local state: %w", err)
 		}
@@ -53,7 +55,7 @@ var setTeamsUsersCmd = &cobra.Command{
 	Short: "Set members of a team in local configuration",
 	Args:  cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		cfg, err := loadState()
 		if err != nil {
 			return fmt.Errorf("failed to load local state: %w", err)
 		}
@@ -74,7 +76,7 @@ var setTeamsMentorsCmd = &cobra.Command{
 	Short: "Set mentors of a team in local configuration",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		cfg, err := loadState()
 		if err != nil {
 			return fmt.Errorf("failed to load local state: %w", err)
 		}
@@ -91,6 +93,57 @@ var setTeamsMentorsCmd = &cobra.Command{
 	},
 }
 
+var setTeamsMaintainersCmd = &cobra.Command{
+	Use:   "set-team-maintainers TEAM [USER ...]",
+	Short: "Set maintainers of a team in local configuration",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		if err = setTeamMaintainers(args[0], args[1:], cfg); err != nil {
+			return fmt.Errorf("failed to set team maintainers: %w", err)
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+
+		return nil
+	},
+}
+
+var renameTeamCmd = &cobra.Command{
+	Use:   "rename-team TEAM NEW-NAME",
+	Short: "Rename a team in local configuration, keeping its ID so that push renames it in GitHub",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		_, hasOverride := cfg.TeamOverrides[args[0]]
+		if err = cfg.RenameTeam(args[0], args[1]); err != nil {
+			return fmt.Errorf("failed to rename team: %w", err)
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+
+		if hasOverride {
+			if err = persistence.StoreOverrides(overrideFilename, cfg); err != nil {
+				return fmt.Errorf("failed to store overrides: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
+
 func addTeamsToConfig(ctx context.Context, addTeams []string, cfg *config.Config, ghClient *gh.Client) error {
 	for _, addTeam := range addTeams {
 		t, _, err := ghClient.Teams.GetTeamBySlug(ctx, orgName, addTeam)
@@ -103,6 +156,7 @@ func addTeamsToConfig(ctx context.Context, addTeams []string, cfg *config.Config
 		team := &config.TeamConfig{
 			ID:          t.GetNodeID(),
 			RESTID:      t.GetID(),
+			Slug:        t.GetSlug(),
 			Description: t.GetDescription(),
 			Privacy:     config.ParsePrivacyFromREST(t.GetPrivacy()),
 		}
@@ -129,6 +183,27 @@ func addTeamsToConfig(ctx context.Context, addTeams []string, cfg *config.Config
 			}
 			page = resp.NextPage
 		}
+
+		page = 0
+		for {
+			maintainers, resp, err := ghClient.Teams.ListTeamMembersBySlug(ctx, orgName, addTeam, &gh.TeamListTeamMembersOptions{
+				Role: "maintainer",
+				ListOptions: gh.ListOptions{
+					Page:    page,
+					PerPage: 100,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get maintainers of team: %w", err)
+			}
+			for _, maintainer := range maintainers {
+				team.Maintainers = append(team.Maintainers, maintainer.GetLogin())
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			page = resp.NextPage
+		}
 		cfg.Teams[t.GetName()] = team
 		cfg.AllTeams[t.GetName()] = team
 	}
@@ -166,6 +241,21 @@ func setTeamMentors(team string, users []string, cfg *config.Config) error {
 	return nil
 }
 
+func setTeamMaintainers(team string, users []string, cfg *config.Config) error {
+	maintainers, err := findUsers(cfg, users)
+	if err != nil {
+		return fmt.Errorf("unable to find users: %w", err)
+	}
+	teamConfig, ok := cfg.AllTeams[team]
+	if !ok {
+		return fmt.Errorf("unknown team %q", team)
+	}
+	teamConfig.Maintainers = stringset.New(maintainers...).Elements()
+	cfg.AllTeams[team] = teamConfig
+
+	return nil
+}
+
 func addTeamMembers(team string, users []string, cfg *config.Config) error {
 	teamConfig, ok := cfg.AllTeams[team]
 	if !ok {
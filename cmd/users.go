@@ -6,6 +6,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	gh "github.com/google/go-github/v79/github"
@@ -22,6 +24,7 @@ var (
 
 func init() {
 	rootCmd.AddCommand(addUsersCmd)
+	rootCmd.AddCommand(migrateLoginCmd)
 
 	addUsersCmd.Flags().StringSliceVar(&addTeams, "teams", []string{}, "Add the users to the specified teams in the local cache")
 }
@@ -36,7 +39,7 @@ var addUsersCmd = &cobra.Command{
 			return fmt.Errorf("failed to create github client: %w", err)
 		}
 
-		cfg, err := persistence.LoadState(configFilename, overrideFilename)
+		cfg, err := loadState()
 		if err != nil {
 			return fmt.Errorf("failed to load local state: %w", err)
 		}
@@ -59,6 +62,70 @@ var addUsersCmd = &cobra.Command{
 	},
 }
 
+var migrateLoginCmd = &cobra.Command{
+	Use:   "migrate-login OLD-LOGIN NEW-LOGIN",
+	Short: "Rename a member renamed in GitHub, in all teams, repositories and overrides of the local configuration",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ghClient, err := github.NewClientFromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to create github client: %w", err)
+		}
+
+		cfg, err := loadState()
+		if err != nil {
+			return fmt.Errorf("failed to load local state: %w", err)
+		}
+
+		oldLogin, newLogin := args[0], args[1]
+		member, ok := cfg.Members[oldLogin]
+		if !ok {
+			return fmt.Errorf("member %q not found", oldLogin)
+		}
+		u, _, err := ghClient.Users.Get(cmd.Context(), newLogin)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub user %q: %w", newLogin, err)
+		}
+		if member.ID != "" && member.ID != u.GetNodeID() {
+			return fmt.Errorf("GitHub user %q has ID %q, which is not the ID %q of member %q", newLogin, u.GetNodeID(), member.ID, oldLogin)
+		}
+
+		if err = migrateLogin(cfg, oldLogin, newLogin); err != nil {
+			return err
+		}
+
+		if err = persistence.StoreState(configFilename, cfg); err != nil {
+			return fmt.Errorf("failed to store state to config: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// migrateLogin renames the member oldLogin into newLogin in the local
+// configuration and in the override file.
+func migrateLogin(cfg *config.Config, oldLogin, newLogin string) error {
+	var inOverrides bool
+	for _, override := range cfg.TeamOverrides {
+		if slices.Contains(override.Members, oldLogin) || slices.Contains(override.Mentors, oldLogin) {
+			inOverrides = true
+		}
+	}
+	if err := cfg.RenameMember(oldLogin, newLogin); err != nil {
+		return fmt.Errorf("failed to migrate login %q to %q: %w", oldLogin, newLogin, err)
+	}
+	if id, ok := cfg.SharedPeople.Owner(oldLogin); ok {
+		fmt.Printf("[WARN] %q is an account of %s in %s, rename it there as well\n", oldLogin, id, organizationsFilename)
+	}
+	if !inOverrides {
+		return nil
+	}
+	if err := persistence.StoreOverrides(overrideFilename, cfg); err != nil {
+		return fmt.Errorf("failed to store overrides: %w", err)
+	}
+	return nil
+}
+
 func addUsersToConfig(ctx context.Context, addUsers []string, cfg *config.Config, ghClient *gh.Client) error {
 	for _, addUser := range addUsers {
 		u, _, err := ghClient.Users.Get(ctx, addUser)
@@ -74,26 +141,55 @@ func addUsersToConfig(ctx context.Context, addUsers []string, cfg *config.Config
 	return nil
 }
 
+// findUser returns the login of the member identified by s, which is either
+// their login, something identifying the person behind it in the people
+// registry or a part of their name.
 func findUser(config *config.Config, s string) (string, error) {
 	// First, try to find users by exact match of the Github username.
 	if _, ok := config.Members[s]; ok {
 		return s, nil
 	}
 
-	// Second, try to find githubUsernames by substring matching their name.
-	var githubUsernames []string
+	// Second, try to find the members of the person with ID s, before
+	// matching s against the names of the other people and members.
+	if _, ok := config.AllPeople()[s]; ok {
+		if logins := config.MemberLogins(s); len(logins) != 0 {
+			return uniqueUser(s, logins)
+		}
+	}
+
+	// Third, try to find the members of the people matching s.
+	githubUsernames := map[string]struct{}{}
+	for _, id := range config.AllPeople().Find(s) {
+		for _, login := range config.MemberLogins(id) {
+			githubUsernames[login] = struct{}{}
+		}
+	}
+
+	// Fourth, try to find githubUsernames by substring matching their name.
 	for githubUsername, user := range config.Members {
 		if strings.Contains(strings.ToLower(user.Name), strings.ToLower(s)) {
-			githubUsernames = append(githubUsernames, githubUsername)
+			githubUsernames[githubUsername] = struct{}{}
 		}
 	}
-	switch len(githubUsernames) {
+
+	logins := make([]string, 0, len(githubUsernames))
+	for login := range githubUsernames {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return uniqueUser(s, logins)
+}
+
+// uniqueUser returns the only login of logins found for s.
+func uniqueUser(s string, logins []string) (string, error) {
+	switch len(logins) {
 	case 0:
 		return "", fmt.Errorf("%s: user not found", s)
 	case 1:
-		return githubUsernames[0], nil
+		return logins[0], nil
 	default:
-		return "", fmt.Errorf("%s: ambiguous user (found %s)", s, strings.Join(githubUsernames, ", "))
+		return "", fmt.Errorf("%s: ambiguous user (found %s)", s, strings.Join(logins, ", "))
 	}
 }
 
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cilium/team-manager/pkg/webhook"
+)
+
+var (
+	webhookEvent string
+	webhookURL   string
+)
+
+func init() {
+	rootCmd.AddCommand(webhookCmd)
+	webhookCmd.AddCommand(replayWebhookCmd)
+
+	replayWebhookCmd.Flags().StringVar(&webhookEvent, "event", "", "Type of the event of the payloads, e.g. 'membership'")
+	replayWebhookCmd.Flags().StringVar(&webhookURL, "url", "", "URL of the webhook of a 'serve' instance to send the payloads to, signed with the WEBHOOK_SECRET environment variable. If empty, only print the entities affected by each payload")
+	replayWebhookCmd.MarkFlagRequired("event")
+}
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Work with the GitHub webhooks received by 'serve'",
+}
+
+var replayWebhookCmd = &cobra.Command{
+	Use:   "replay PAYLOAD...",
+	Short: "Replay recorded GitHub webhook payloads",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		secret := os.Getenv("WEBHOOK_SECRET")
+		if webhookURL != "" && secret == "" {
+			return fmt.Errorf("the WEBHOOK_SECRET environment variable is required to sign the payloads")
+		}
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		for _, file := range args {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			if webhookURL == "" {
+				e, err := webhook.Parse(webhookEvent, data)
+				switch {
+				case errors.Is(err, webhook.ErrIgnored):
+					fmt.Printf("%s: %s event ignored\n", file, webhookEvent)
+				case err != nil:
+					return fmt.Errorf("%s: %w", file, err)
+				default:
+					fmt.Printf("%s: %s\n", file, e)
+				}
+				continue
+			}
+
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodPost, webhookURL, bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(webhook.EventHeader, webhookEvent)
+			req.Header.Set(webhook.DeliveryHeader, fmt.Sprintf("replay-%d", time.Now().UnixNano()))
+			req.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte(secret), data))
+			resp, err := client.Do(req)
+			if err != nil {
+				return fmt.Errorf("failed to send %s: %w", file, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("failed to send %s: %s: %s", file, resp.Status, bytes.TrimSpace(body))
+			}
+			fmt.Printf("%s: %s\n", file, resp.Status)
+		}
+		return nil
+	},
+}
@@ -13,6 +13,7 @@ require (
 	github.com/spf13/cobra v1.10.1
 	golang.org/x/oauth2 v0.33.0
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package access computes the effective permissions of users on the
+// repositories of an organization from its configuration.
+package access
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// permissionRanks orders the repository permissions from the lowest to the
+// highest one.
+var permissionRanks = map[string]int{
+	"READ":     1,
+	"TRIAGE":   2,
+	"WRITE":    3,
+	"MAINTAIN": 4,
+	"ADMIN":    5,
+}
+
+// Grant is one of the ways a user is given a permission on a repository.
+type Grant struct {
+	// Permission given by this grant, e.g. 'WRITE'.
+	Permission string `json:"permission"`
+
+	// Owner is true if the permission is given by being an owner of the
+	// organization.
+	Owner bool `json:"owner,omitempty"`
+
+	// Chain is the list of teams through which the permission is given,
+	// starting with the team the user is a member of and ending with the
+	// team given the permission on the repository. Each team is a child of
+	// the next one. Empty if the permission is given directly to the user.
+	Chain []string `json:"chain,omitempty"`
+}
+
+func (g Grant) String() string {
+	switch {
+	case g.Owner:
+		return fmt.Sprintf("%s as organization owner", g.Permission)
+	case len(g.Chain) == 0:
+		return fmt.Sprintf("%s directly", g.Permission)
+	default:
+		return fmt.Sprintf("%s via team %s", g.Permission, strings.Join(g.Chain, " > "))
+	}
+}
+
+// Access is the effective permission of a user on a repository.
+type Access struct {
+	Repository string `json:"repository"`
+	User       string `json:"user"`
+
+	// Permission is the highest permission of all grants.
+	Permission string `json:"permission"`
+
+	// Grants are all the ways the user is given access to the repository,
+	// sorted from the highest permission to the lowest one.
+	Grants []Grant `json:"grants"`
+}
+
+// ForRepository returns the effective permissions of all users with access to
+// the given repository, sorted by user. Expired team memberships don't grant
+// any access.
+func ForRepository(cfg *config.Config, repoName config.RepositoryName) ([]Access, error) {
+	repo, ok := cfg.Repositories[repoName]
+	if !ok {
+		return nil, fmt.Errorf("repository %q not found", repoName)
+	}
+
+	grants := map[string][]Grant{}
+	for login, member := range cfg.Members {
+		if member.Role == config.OrgRoleAdmin {
+			grants[login] = append(grants[login], Grant{Permission: "ADMIN", Owner: true})
+		}
+	}
+
+	now := time.Now()
+	for permission, usersOrTeams := range repo {
+		perm := permission.GetPermission()
+		for _, userOrTeam := range usersOrTeams {
+			if permission.IsUser() {
+				login := string(userOrTeam)
+				grants[login] = append(grants[login], Grant{Permission: perm})
+				continue
+			}
+			team, ok := cfg.AllTeams[string(userOrTeam)]
+			if !ok {
+				return nil, fmt.Errorf("team %q of repository %q not found", userOrTeam, repoName)
+			}
+			// Child teams inherit the permissions of their parents.
+			teamNames := append([]string{string(userOrTeam)}, team.Descendents()...)
+			for _, teamName := range teamNames {
+				chain := teamChain(cfg, teamName, string(userOrTeam))
+				for _, login := range cfg.AllTeams[teamName].ActiveMembers(now) {
+					grants[login] = append(grants[login], Grant{Permission: perm, Chain: chain})
+				}
+			}
+		}
+	}
+
+	accesses := make([]Access, 0, len(grants))
+	for login, userGrants := range grants {
+		sortGrants(userGrants)
+		accesses = append(accesses, Access{
+			Repository: string(repoName),
+			User:       login,
+			Permission: userGrants[0].Permission,
+			Grants:     userGrants,
+		})
+	}
+	sort.Slice(accesses, func(i, j int) bool {
+		return accesses[i].User < accesses[j].User
+	})
+	return accesses, nil
+}
+
+// ForUser returns the effective permissions of the given user on all
+// repositories, sorted by repository.
+func ForUser(cfg *config.Config, login string) ([]Access, error) {
+	repoNames := make([]string, 0, len(cfg.Repositories))
+	for repoName := range cfg.Repositories {
+		repoNames = append(repoNames, string(repoName))
+	}
+	sort.Strings(repoNames)
+
+	var accesses []Access
+	for _, repoName := range repoNames {
+		repoAccesses, err := ForRepository(cfg, config.RepositoryName(repoName))
+		if err != nil {
+			return nil, err
+		}
+		for _, access := range repoAccesses {
+			if access.User == login {
+				accesses = append(accesses, access)
+			}
+		}
+	}
+	return accesses, nil
+}
+
+// ForTeams returns the effective permission of all teams with access to the
+// given repository, including the permissions inherited from parent teams.
+func ForTeams(cfg *config.Config, repoName config.RepositoryName) (map[string]string, error) {
+	repo, ok := cfg.Repositories[repoName]
+	if !ok {
+		return nil, fmt.Errorf("repository %q not found", repoName)
+	}
+
+	permissions := map[string]string{}
+	for permission, usersOrTeams := range repo {
+		if permission.IsUser() {
+			continue
+		}
+		perm := permission.GetPermission()
+		for _, teamName := range usersOrTeams {
+			team, ok := cfg.AllTeams[string(teamName)]
+			if !ok {
+				return nil, fmt.Errorf("team %q of repository %q not found", teamName, repoName)
+			}
+			for _, name := range append([]string{string(teamName)}, team.Descendents()...) {
+				if !HasPermission(permissions[name], perm) {
+					permissions[name] = perm
+				}
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// HasPermission returns true if permission is the same or higher than the
+// required one.
+func HasPermission(permission, required string) bool {
+	return permissionRanks[permission] >= permissionRanks[required]
+}
+
+// teamChain returns the list of teams from teamName up to its ancestor.
+func teamChain(cfg *config.Config, teamName, ancestor string) []string {
+	chain := []string{teamName}
+	for teamName != ancestor {
+		team, ok := cfg.AllTeams[teamName]
+		if !ok || team.ParentTeam == "" {
+			break
+		}
+		teamName = string(team.ParentTeam)
+		chain = append(chain, teamName)
+	}
+	return chain
+}
+
+// sortGrants sorts the grants from the highest permission to the lowest one.
+// Grants with the same permission are sorted by their description.
+func sortGrants(grants []Grant) {
+	sort.Slice(grants, func(i, j int) bool {
+		ri, rj := permissionRanks[grants[i].Permission], permissionRanks[grants[j].Permission]
+		if ri != rj {
+			return ri > rj
+		}
+		return grants[i].String() < grants[j].String()
+	})
+}
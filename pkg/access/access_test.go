@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package access
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func accessConfig() *config.Config {
+	cfg := &config.Config{
+		Members: map[string]config.User{
+			"alice": {Role: config.OrgRoleAdmin},
+			"bob":   {Role: config.OrgRoleMember},
+			"carol": {},
+			"dave":  {},
+		},
+		Teams: map[string]*config.TeamConfig{
+			"parent": {
+				Members: []string{"bob"},
+				Children: map[string]*config.TeamConfig{
+					"child": {
+						ParentTeam:   "parent",
+						Members:      []string{"carol", "dave"},
+						MembersUntil: map[string]config.Date{"dave": config.NewDate(time.Now().AddDate(0, 0, -2))},
+					},
+				},
+			},
+		},
+		Repositories: map[config.RepositoryName]config.Repository{
+			"cilium": {
+				"WRITE":         {"parent"},
+				"READ":          {"child"},
+				"USER-MAINTAIN": {"carol"},
+			},
+			"docs": {
+				"ADMIN": {"child"},
+			},
+			"empty": {},
+		},
+	}
+	cfg.IndexTeams()
+	return cfg
+}
+
+func TestForRepository(t *testing.T) {
+	tests := []struct {
+		repo    config.RepositoryName
+		want    []Access
+		wantErr string
+	}{
+		{
+			repo: "cilium",
+			want: []Access{
+				{Repository: "cilium", User: "alice", Permission: "ADMIN", Grants: []Grant{
+					{Permission: "ADMIN", Owner: true},
+				}},
+				{Repository: "cilium", User: "bob", Permission: "WRITE", Grants: []Grant{
+					{Permission: "WRITE", Chain: []string{"parent"}},
+				}},
+				{Repository: "cilium", User: "carol", Permission: "MAINTAIN", Grants: []Grant{
+					{Permission: "MAINTAIN"},
+					{Permission: "WRITE", Chain: []string{"child", "parent"}},
+					{Permission: "READ", Chain: []string{"child"}},
+				}},
+			},
+		},
+		{
+			repo: "empty",
+			want: []Access{
+				{Repository: "empty", User: "alice", Permission: "ADMIN", Grants: []Grant{
+					{Permission: "ADMIN", Owner: true},
+				}},
+			},
+		},
+		{
+			repo:    "unknown",
+			wantErr: `repository "unknown" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.repo), func(t *testing.T) {
+			got, err := ForRepository(accessConfig(), tt.repo)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ForRepository() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ForRepository() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ForRepository() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForRepositoryUnknownTeam(t *testing.T) {
+	cfg := accessConfig()
+	cfg.Repositories["cilium"]["TRIAGE"] = []config.TeamOrMemberName{"ghost"}
+	want := `team "ghost" of repository "cilium" not found`
+	if _, err := ForRepository(cfg, "cilium"); err == nil || err.Error() != want {
+		t.Errorf("ForRepository() error = %v, want %q", err, want)
+	}
+	if _, err := ForTeams(cfg, "cilium"); err == nil || err.Error() != want {
+		t.Errorf("ForTeams() error = %v, want %q", err, want)
+	}
+}
+
+func TestForUser(t *testing.T) {
+	got, err := ForUser(accessConfig(), "carol")
+	if err != nil {
+		t.Fatalf("ForUser() error = %v", err)
+	}
+	want := []Access{
+		{Repository: "cilium", User: "carol", Permission: "MAINTAIN", Grants: []Grant{
+			{Permission: "MAINTAIN"},
+			{Permission: "WRITE", Chain: []string{"child", "parent"}},
+			{Permission: "READ", Chain: []string{"child"}},
+		}},
+		{Repository: "docs", User: "carol", Permission: "ADMIN", Grants: []Grant{
+			{Permission: "ADMIN", Chain: []string{"child"}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ForUser() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Expired memberships don't grant any access.
+	if got, err := ForUser(accessConfig(), "dave"); err != nil || len(got) != 0 {
+		t.Errorf("ForUser() = %+v, %v, want no access", got, err)
+	}
+}
+
+func TestForTeams(t *testing.T) {
+	tests := []struct {
+		repo config.RepositoryName
+		want map[string]string
+	}{
+		{"cilium", map[string]string{"parent": "WRITE", "child": "WRITE"}},
+		{"docs", map[string]string{"child": "ADMIN"}},
+		{"empty", map[string]string{}},
+	}
+	for _, tt := range tests {
+		got, err := ForTeams(accessConfig(), tt.repo)
+		if err != nil {
+			t.Fatalf("ForTeams(%q) error = %v", tt.repo, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ForTeams(%q) = %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestGrantString(t *testing.T) {
+	tests := []struct {
+		grant Grant
+		want  string
+	}{
+		{Grant{Permission: "ADMIN", Owner: true}, "ADMIN as organization owner"},
+		{Grant{Permission: "MAINTAIN"}, "MAINTAIN directly"},
+		{Grant{Permission: "WRITE", Chain: []string{"child", "parent"}}, "WRITE via team child > parent"},
+	}
+	for _, tt := range tests {
+		if got := tt.grant.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		permission, required string
+		want                 bool
+	}{
+		{"ADMIN", "READ", true},
+		{"WRITE", "WRITE", true},
+		{"TRIAGE", "WRITE", false},
+		{"", "READ", false},
+		{"READ", "", true},
+	}
+	for _, tt := range tests {
+		if got := HasPermission(tt.permission, tt.required); got != tt.want {
+			t.Errorf("HasPermission(%q, %q) = %v, want %v", tt.permission, tt.required, got, tt.want)
+		}
+	}
+}
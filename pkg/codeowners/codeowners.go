@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package codeowners checks and generates the CODEOWNERS files of the
+// repositories of an organization from its configuration.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cilium/team-manager/pkg/access"
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// requiredPermission is the permission that owners need on the repository,
+// otherwise GitHub silently ignores them.
+const requiredPermission = "WRITE"
+
+// Rule is a line of a CODEOWNERS file.
+type Rule struct {
+	// Line is the line number of the rule in the file, starting at 1.
+	Line int
+
+	// Pattern is the path pattern matched by the rule.
+	Pattern string
+
+	// Owners are the users, teams or emails owning the matching paths.
+	Owners []string
+}
+
+// Problem is an owner of a CODEOWNERS file that GitHub would ignore.
+type Problem struct {
+	Line    int
+	Owner   string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Owner, p.Message)
+}
+
+// Parse parses the rules of a CODEOWNERS file. Blank lines and comments are
+// skipped.
+func Parse(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rule := Rule{Line: line, Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Check returns the owners of the given rules that GitHub would ignore for
+// the given repository: teams that don't exist or don't have write access to
+// the repository, and users that don't belong to the organization, aren't
+// outside collaborators or don't have write access to the repository. Email
+// owners can't be verified and are skipped.
+func Check(cfg *config.Config, repoName config.RepositoryName, rules []Rule) ([]Problem, error) {
+	teamPermissions, err := access.ForTeams(cfg, repoName)
+	if err != nil {
+		return nil, err
+	}
+	accesses, err := access.ForRepository(cfg, repoName)
+	if err != nil {
+		return nil, err
+	}
+	userPermissions := make(map[string]string, len(accesses))
+	for _, a := range accesses {
+		userPermissions[strings.ToLower(a.User)] = a.Permission
+	}
+	teamsBySlug := make(map[string]string, len(cfg.AllTeams))
+	for teamName := range cfg.AllTeams {
+		teamsBySlug[cfg.TeamSlug(teamName)] = teamName
+	}
+	logins := make(map[string]struct{}, len(cfg.Members)+len(cfg.Collaborators))
+	for login := range cfg.Members {
+		logins[strings.ToLower(login)] = struct{}{}
+	}
+	for login := range cfg.Collaborators {
+		logins[strings.ToLower(login)] = struct{}{}
+	}
+
+	var problems []Problem
+	for _, rule := range rules {
+		for _, owner := range rule.Owners {
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			name := strings.ToLower(strings.TrimPrefix(owner, "@"))
+			problem := Problem{Line: rule.Line, Owner: owner}
+
+			if org, teamSlug, isTeam := strings.Cut(name, "/"); isTeam {
+				teamName, ok := teamsBySlug[teamSlug]
+				switch {
+				case !strings.EqualFold(org, cfg.Organization):
+					problem.Message = fmt.Sprintf("team does not belong to organization %q", cfg.Organization)
+				case !ok:
+					problem.Message = "team does not exist"
+				case !access.HasPermission(teamPermissions[teamName], requiredPermission):
+					problem.Message = missingPermission(teamPermissions[teamName], repoName)
+				default:
+					continue
+				}
+				problems = append(problems, problem)
+				continue
+			}
+
+			_, ok := logins[name]
+			switch {
+			case !ok:
+				problem.Message = "user is neither a member of the organization nor an outside collaborator"
+			case !access.HasPermission(userPermissions[name], requiredPermission):
+				problem.Message = missingPermission(userPermissions[name], repoName)
+			default:
+				continue
+			}
+			problems = append(problems, problem)
+		}
+	}
+	return problems, nil
+}
+
+func missingPermission(permission string, repoName config.RepositoryName) string {
+	if permission == "" {
+		return fmt.Sprintf("no access to repository %q, %s is required", repoName, requiredPermission)
+	}
+	return fmt.Sprintf("%s permission on repository %q, %s is required", permission, repoName, requiredPermission)
+}
+
+// Generate returns a CODEOWNERS file for the given repository from the code
+// owners declared by the teams. Since the last matching pattern takes
+// precedence, patterns are ordered from the least to the most specific one,
+// see lessSpecific.
+func Generate(cfg *config.Config, repoName config.RepositoryName) ([]byte, error) {
+	if _, ok := cfg.Repositories[repoName]; !ok {
+		return nil, fmt.Errorf("repository %q not found", repoName)
+	}
+
+	owners := map[string][]string{}
+	for teamName, team := range cfg.AllTeams {
+		for _, pattern := range team.CodeOwners[repoName] {
+			owners[pattern] = append(owners[pattern], fmt.Sprintf("@%s/%s", cfg.Organization, cfg.TeamSlug(teamName)))
+		}
+	}
+	patterns := make([]string, 0, len(owners))
+	for pattern := range owners {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return lessSpecific(patterns[i], patterns[j])
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by team-manager from the code owners of the teams, do not edit.\n")
+	for _, pattern := range patterns {
+		sort.Strings(owners[pattern])
+		fmt.Fprintf(&buf, "%s %s\n", pattern, strings.Join(owners[pattern], " "))
+	}
+	return buf.Bytes(), nil
+}
+
+// lessSpecific returns true if pattern a is less specific than pattern b, i.e.
+// if it has fewer path segments, not counting "**", or the same number of
+// segments but it isn't anchored to the root while b is, or it has fewer
+// characters that aren't wildcards. Patterns as specific as each other are
+// sorted alphabetically. For instance, "*" comes before "docs/", which comes
+// before "/docs/" and "/docs/*.md".
+func lessSpecific(a, b string) bool {
+	if sa, sb := segments(a), segments(b); sa != sb {
+		return sa < sb
+	}
+	if aa, ab := strings.HasPrefix(a, "/"), strings.HasPrefix(b, "/"); aa != ab {
+		return ab
+	}
+	if la, lb := literals(a), literals(b); la != lb {
+		return la < lb
+	}
+	return a < b
+}
+
+// segments returns the number of path segments of a pattern, not counting the
+// "**" ones that match any number of segments.
+func segments(pattern string) int {
+	var n int
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if segment != "" && segment != "**" {
+			n++
+		}
+	}
+	return n
+}
+
+// literals returns the number of characters of a pattern that aren't
+// wildcards.
+func literals(pattern string) int {
+	return len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+}
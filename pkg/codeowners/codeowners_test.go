@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package codeowners
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func testConfig() *config.Config {
+	cfg := &config.Config{
+		Organization: "cilium",
+		Members: map[string]config.User{
+			"aanm":        {},
+			"joestringer": {},
+			"reader":      {},
+		},
+		Collaborators: map[string]config.OutsideCollaborator{
+			"outsider": {Reason: "Maintains the docs", Sponsor: "aanm"},
+		},
+		Teams: map[string]*config.TeamConfig{
+			"sig-datapath": {
+				Members: []string{"aanm"},
+				CodeOwners: map[config.RepositoryName][]string{
+					"cilium": {"/bpf/", "*"},
+				},
+				Children: map[string]*config.TeamConfig{
+					"sig-datapath-bpf": {
+						ParentTeam: "sig-datapath",
+						Members:    []string{"joestringer"},
+						CodeOwners: map[config.RepositoryName][]string{
+							"cilium": {"/bpf/lib/", "/bpf/"},
+						},
+					},
+				},
+			},
+			"Documentation": {
+				Slug:    "docs",
+				Members: []string{"reader"},
+				CodeOwners: map[config.RepositoryName][]string{
+					"cilium": {"/docs/*.md", "docs/", "**/README.md"},
+				},
+			},
+		},
+		Repositories: map[config.RepositoryName]config.Repository{
+			"cilium": {
+				"WRITE":      {"sig-datapath"},
+				"READ":       {"Documentation"},
+				"USER-WRITE": {"outsider"},
+			},
+		},
+	}
+	cfg.IndexTeams()
+	config.SetParentNames(cfg.AllTeams)
+	return cfg
+}
+
+func TestParse(t *testing.T) {
+	data := `# Owners of the repository.
+
+*                 @cilium/sig-datapath
+/bpf/   @cilium/sig-datapath-bpf  aanm@example.com # BPF programs
+docs/ # nobody
+	/docs/*.md @cilium/docs @outsider
+`
+	rules, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Rule{
+		{Line: 3, Pattern: "*", Owners: []string{"@cilium/sig-datapath"}},
+		{Line: 4, Pattern: "/bpf/", Owners: []string{"@cilium/sig-datapath-bpf", "aanm@example.com"}},
+		{Line: 5, Pattern: "docs/"},
+		{Line: 6, Pattern: "/docs/*.md", Owners: []string{"@cilium/docs", "@outsider"}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("Parse() = %+v, want %+v", rules, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		owners []string
+		want   []string
+	}{
+		{
+			name:   "team with write access",
+			owners: []string{"@cilium/sig-datapath"},
+		},
+		{
+			name:   "child team inheriting write access, case insensitive",
+			owners: []string{"@Cilium/SIG-Datapath-BPF"},
+		},
+		{
+			name:   "team referred by its slug",
+			owners: []string{"@cilium/docs"},
+			want:   []string{`READ permission on repository "cilium", WRITE is required`},
+		},
+		{
+			name:   "team referred by its name instead of its slug",
+			owners: []string{"@cilium/documentation"},
+			want:   []string{"team does not exist"},
+		},
+		{
+			name:   "team of another organization",
+			owners: []string{"@other/sig-datapath"},
+			want:   []string{`team does not belong to organization "cilium"`},
+		},
+		{
+			name:   "members with write access",
+			owners: []string{"@aanm", "@JoeStringer"},
+		},
+		{
+			name:   "outside collaborator with write access",
+			owners: []string{"@outsider"},
+		},
+		{
+			name:   "member without write access",
+			owners: []string{"@reader"},
+			want:   []string{`READ permission on repository "cilium", WRITE is required`},
+		},
+		{
+			name:   "unknown user",
+			owners: []string{"@nobody"},
+			want:   []string{"user is neither a member of the organization nor an outside collaborator"},
+		},
+		{
+			name:   "emails are skipped",
+			owners: []string{"nobody@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := Check(testConfig(), "cilium", []Rule{{Line: 7, Pattern: "*", Owners: tt.owners}})
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			var got []string
+			for _, problem := range problems {
+				if problem.Line != 7 {
+					t.Errorf("Check() problem %q on line %d, want 7", problem, problem.Line)
+				}
+				got = append(got, problem.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Check(testConfig(), "unknown", nil); err == nil {
+		t.Errorf("Check() of an unknown repository succeeded")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	data, err := Generate(testConfig(), "cilium")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want := `# Generated by team-manager from the code owners of the teams, do not edit.
+* @cilium/sig-datapath
+docs/ @cilium/docs
+**/README.md @cilium/docs
+/bpf/ @cilium/sig-datapath @cilium/sig-datapath-bpf
+/bpf/lib/ @cilium/sig-datapath-bpf
+/docs/*.md @cilium/docs
+`
+	if string(data) != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", data, want)
+	}
+
+	// The generated file is valid.
+	rules, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rules) != 6 {
+		t.Errorf("Parse() of the generated file returned %d rules, want 6", len(rules))
+	}
+
+	if _, err := Generate(testConfig(), "unknown"); err == nil {
+		t.Errorf("Generate() of an unknown repository succeeded")
+	}
+}
+
+func TestLessSpecific(t *testing.T) {
+	// From the least to the most specific pattern.
+	patterns := []string{"*", "*.go", "docs/", "/docs/", "/docs/**", "docs/*.md", "/docs/*.md", "/docs/api/"}
+	for i := range patterns {
+		for j := range patterns {
+			if got, want := lessSpecific(patterns[i], patterns[j]), i < j; got != want {
+				t.Errorf("lessSpecific(%q, %q) = %v, want %v", patterns[i], patterns[j], got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"sort"
+	"time"
+)
+
+// ExpiringCollaborators returns the logins of the outside collaborators whose
+// access expired, or expires before the given deadline, sorted by expiry.
+func ExpiringCollaborators(cfg *Config, deadline time.Time) []string {
+	var logins []string
+	for login, collaborator := range cfg.Collaborators {
+		if collaborator.Expires.IsZero() || !collaborator.Expires.Before(deadline) {
+			continue
+		}
+		logins = append(logins, login)
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		ei, ej := cfg.Collaborators[logins[i]].Expires, cfg.Collaborators[logins[j]].Expires
+		if !ei.Equal(ej.Time) {
+			return ei.Before(ej.Time)
+		}
+		return logins[i] < logins[j]
+	})
+	return logins
+}
@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 )
@@ -66,7 +68,15 @@ func GraphQLPerm2RestAPIPerm(perm string) string {
 
 type Repository map[Permission][]TeamOrMemberName
 
+// CurrentVersion is the version of the configuration file format written by
+// this team-manager. Files with an older version are migrated, see the
+// migration package.
+const CurrentVersion = 1
+
 type Config struct {
+	// Version of the configuration file format.
+	Version int `json:"version,omitempty" yaml:"version,omitempty"`
+
 	// Organization being managed.
 	Organization string `json:"organization,omitempty" yaml:"organization,omitempty"`
 
@@ -80,6 +90,10 @@ type Config struct {
 	// Members maps the github login to a User.
 	Members map[string]User `json:"members,omitempty" yaml:"members,omitempty"`
 
+	// People maps the ID of each person to the person behind one or more
+	// GitHub accounts. They are only stored locally.
+	People People `json:"people,omitempty" yaml:"people,omitempty"`
+
 	// Outside collaborators maps the github login to a User.
 	Collaborators map[string]OutsideCollaborator `json:"outsideCollaborators,omitempty" yaml:"outsideCollaborators,omitempty"`
 
@@ -90,6 +104,14 @@ type Config struct {
 	// assignments.
 	ExcludeCRAFromAllTeams []string `json:"excludeCodeReviewAssignmentFromAllTeams" yaml:"excludeCodeReviewAssignmentFromAllTeams"`
 
+	// Protected contains the members, teams and repositories that can never
+	// be removed, deleted or demoted by a push.
+	Protected Protected `json:"protected,omitempty" yaml:"protected,omitempty"`
+
+	// Profiles are named recipes to onboard new members. They are only
+	// stored locally.
+	Profiles map[string]Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+
 	// AllTeams is an index of all teams in the organization
 	// maps the team name to its config. GitHub doesn't allow duplicated team
 	// names, so we can do safely do this.
@@ -99,6 +121,46 @@ type Config struct {
 	// maps the team name to its config. GitHub doesn't allow duplicated team
 	// names, so we can do safely do this.
 	TeamOverrides map[string]*OverrideTeamConfig `json:"-" yaml:"-"`
+
+	// Invitations are the invitations to join the organization that were not
+	// accepted yet, including the ones that failed or expired. They are only
+	// populated when pulling the configuration from GitHub.
+	Invitations []Invitation `json:"-" yaml:"-"`
+
+	// SharedPeople are the people shared with other organizations managed
+	// together. They are not stored in the configuration file.
+	SharedPeople People `json:"-" yaml:"-"`
+}
+
+// Protected lists the org members, teams and repositories that must survive
+// any push, whatever the rest of the configuration says.
+type Protected struct {
+	// Members is a list of logins that can never be removed from the
+	// organization or lose the admin permission of a repository.
+	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
+
+	// Teams is a list of team names that can never be deleted or lose the
+	// admin permission of a repository.
+	Teams []string `json:"teams,omitempty" yaml:"teams,omitempty"`
+
+	// Repositories is a list of repositories in which no admin permission can
+	// be removed or demoted.
+	Repositories []RepositoryName `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+}
+
+// IsMember returns true if the given login is a protected member.
+func (p Protected) IsMember(login string) bool {
+	return slices.Contains(p.Members, login)
+}
+
+// IsTeam returns true if the given team name is a protected team.
+func (p Protected) IsTeam(teamName string) bool {
+	return slices.Contains(p.Teams, teamName)
+}
+
+// IsRepository returns true if the given repository is protected.
+func (p Protected) IsRepository(repoName RepositoryName) bool {
+	return slices.Contains(p.Repositories, repoName)
 }
 
 // This will hold the information from the Team Override File
@@ -123,13 +185,22 @@ func (c *Config) IndexTeams() {
 
 }
 
-func normalizeTeam(team *TeamConfig) {
+func normalizeTeam(team *TeamConfig, now time.Time) {
+	// Expired memberships are removed from GitHub by push.
+	team.Members = team.ActiveMembers(now)
+	team.Maintainers = team.ActiveMaintainers(now)
+	team.Slug = ""
+	team.MembersUntil = nil
+	team.CodeOwners = nil
+	team.Source = nil
 	sort.Strings(team.Members)
 	team.Members = slices.Compact(team.Members)
+	sort.Strings(team.Maintainers)
+	team.Maintainers = slices.Compact(team.Maintainers)
 	team.Mentors = make([]string, 0)
 	team.CodeReviewAssignment.ExcludedMembers = nil
 	for _, child := range team.Children {
-		normalizeTeam(child)
+		normalizeTeam(child, now)
 	}
 }
 
@@ -143,7 +214,8 @@ type NormalizeOpts struct {
 // upstream GitHub configuration.
 //
 // Members excluded from code review assignments are also removed due to lack
-// of support to fetch this configuration in API 2022-11-28.
+// of support to fetch this configuration in API 2022-11-28. Team members whose
+// membership expired are removed as well, since push removes them from GitHub.
 func (c *Config) Normalize(cfg NormalizeOpts) {
 	if !cfg.Repositories {
 		c.Repositories = nil
@@ -158,15 +230,22 @@ func (c *Config) Normalize(cfg NormalizeOpts) {
 		c.Members = nil
 	}
 	if cfg.Teams {
+		now := time.Now()
 		for _, team := range c.Teams {
-			normalizeTeam(team)
+			normalizeTeam(team, now)
 		}
 	} else {
 		c.Teams = nil
 	}
 
+	c.Version = 0
 	c.ExcludeCRAFromAllTeams = nil
+	c.Protected = Protected{}
+	c.Profiles = nil
+	c.People = nil
+	c.SharedPeople = nil
 	c.TeamOverrides = nil
+	c.Invitations = nil
 	c.AllTeams = nil
 	c.IndexTeams()
 }
@@ -215,6 +294,13 @@ func (c *Config) Merge(other *Config) (*Config, error) {
 			slices.Delete(other.ExcludeCRAFromAllTeams, i, i+1)
 		}
 	}
+
+	// Protections are only stored locally.
+	other.Protected = c.Protected
+	other.Profiles = c.Profiles
+	other.People = c.People
+	other.SharedPeople = c.SharedPeople
+
 	// Keep mentors since we can't fetch this information
 	// from GitHub.
 	for otherTeamName, otherTeam := range other.AllTeams {
@@ -234,6 +320,27 @@ func (c *Config) Merge(other *Config) (*Config, error) {
 		}
 	}
 
+	// Keep the expiry of the team memberships, the code owners and the source
+	// of the members since they are only stored locally.
+	for otherTeamName, otherTeam := range other.AllTeams {
+		team, ok := c.AllTeams[otherTeamName]
+		if !ok {
+			continue
+		}
+		otherTeam.CodeOwners = team.CodeOwners
+		otherTeam.Source = team.Source
+		otherTeam.MembersUntil = nil
+		for login, until := range team.MembersUntil {
+			if !slices.Contains(otherTeam.Members, login) {
+				continue
+			}
+			if otherTeam.MembersUntil == nil {
+				otherTeam.MembersUntil = map[string]Date{}
+			}
+			otherTeam.MembersUntil[login] = until
+		}
+	}
+
 	// Keep the code review assignment since we can't fetch this information
 	// from GitHub.
 	for otherTeamName, otherTeam := range other.AllTeams {
@@ -288,10 +395,16 @@ func (c *Config) Merge(other *Config) (*Config, error) {
 func updateTeamIDsFrom(old, newTeams map[string]*TeamConfig) {
 	for newTeamName, newTeam := range newTeams {
 		oldTeam := old[newTeamName]
-		if oldTeam != nil && oldTeam.ID != newTeam.ID {
+		if oldTeam == nil {
+			continue
+		}
+		if oldTeam.ID != newTeam.ID {
 			oldTeam.ID = newTeam.ID
 			oldTeam.RESTID = newTeam.RESTID
 		}
+		if newTeam.Slug != "" {
+			oldTeam.Slug = newTeam.Slug
+		}
 	}
 }
 
@@ -322,18 +435,41 @@ type TeamConfig struct {
 
 	RESTID int64 `json:"restID" yaml:"restID"`
 
+	// Slug is the GitHub slug of this team, used to refer to the team in the
+	// REST API.
+	Slug string `json:"slug,omitempty" yaml:"slug,omitempty"`
+
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 
+	// Source is the optional external group of people that are the members
+	// of this team, synchronized with 'sync-groups'.
+	Source *GroupSource `json:"source,omitempty" yaml:"source,omitempty"`
+
 	// Members is a list of users that belong to this team.
 	Members []string `json:"members,omitempty" yaml:"members,omitempty"`
 
+	// MembersUntil maps the members of this team whose membership expires to
+	// the last day of their membership. In the configuration file, these
+	// members are written as a 'login' and 'until' pair in the list of
+	// members.
+	MembersUntil map[string]Date `json:"-" yaml:"-"`
+
 	// Mentors is a list of users that belong to this team, but opt out of code review notifications
 	// Note 1: Mentors _must_ be in the member list. Lint will warn if they are not
 	Mentors []string `json:"mentors,omitempty" yaml:"mentors,omitempty"`
 
+	// Maintainers is a list of users that belong to this team with the
+	// maintainer role, which allows them to manage the team in GitHub.
+	// Note: Maintainers _must_ be in the member list.
+	Maintainers []string `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+
 	// CodeReviewAssignment is the code review assignment configuration of this team
 	CodeReviewAssignment CodeReviewAssignment `json:"codeReviewAssignment,omitempty" yaml:"codeReviewAssignment,omitempty"`
 
+	// CodeOwners maps repositories to the path patterns owned by this team,
+	// used to generate the CODEOWNERS file of each repository.
+	CodeOwners map[RepositoryName][]string `json:"codeOwners,omitempty" yaml:"codeOwners,omitempty"`
+
 	Privacy TeamPrivacy `json:"privacy,omitempty" yaml:"privacy,omitempty"`
 
 	ParentTeam TeamOrMemberName `json:"-" yaml:"-"`
@@ -341,6 +477,29 @@ type TeamConfig struct {
 	Children map[string]*TeamConfig `json:"children,omitempty" yaml:"children,omitempty"`
 }
 
+// Slugify returns the slug version of the team name. This simply replaces all
+// characters that are not in the following regex `[^a-z0-9]+` with a `-`.
+// It's a simplistic versions of the official's GitHub slug transformation since
+// GitHub changes accents characters as well, for example 'ä' to 'a'.
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+
+	re := regexp.MustCompile("[^a-z0-9]+")
+	s = re.ReplaceAllString(s, "-")
+
+	s = strings.Trim(s, "-")
+	return s
+}
+
+// TeamSlug returns the GitHub slug of the given team, falling back to the
+// slug computed from its name if it was never pulled from GitHub.
+func (c *Config) TeamSlug(teamName string) string {
+	if team, ok := c.AllTeams[teamName]; ok && team.Slug != "" {
+		return team.Slug
+	}
+	return Slugify(teamName)
+}
+
 func (c *TeamConfig) IsAncestorOf(child string) bool {
 	for name, children := range c.Children {
 		if name == child {
@@ -372,11 +531,54 @@ type User struct {
 	// SlackID is the Slack user ID of the person behind this GH account.
 	// The user ID can be found in the UI, under the profile of each user, under "More".
 	SlackID string `json:"slackID,omitempty" yaml:"slackID,omitempty"`
+
+	// Role is the role of this user in the organization. If empty, the role
+	// is not managed by team-manager.
+	Role OrgRole `json:"role,omitempty" yaml:"role,omitempty"`
+}
+
+// OrgRole is the role of a member in the organization.
+type OrgRole string
+
+const (
+	// OrgRoleAdmin are the owners of the organization.
+	OrgRoleAdmin OrgRole = "admin"
+	// OrgRoleMember are the members of the organization without any
+	// administrative rights.
+	OrgRoleMember OrgRole = "member"
+)
+
+// ParseOrgRoleFromGraphQL converts the GraphQL role of an organization member
+// into an OrgRole.
+func ParseOrgRoleFromGraphQL(role githubv4.OrganizationMemberRole) OrgRole {
+	switch role {
+	case githubv4.OrganizationMemberRoleAdmin:
+		return OrgRoleAdmin
+	default:
+		return OrgRoleMember
+	}
+}
+
+// InvitationRole returns the role used when inviting a member to the
+// organization with this role.
+func (r OrgRole) InvitationRole() string {
+	if r == OrgRoleAdmin {
+		return "admin"
+	}
+	return "direct_member"
 }
 
 type OutsideCollaborator struct {
 	// Reason contains the reason why they are an outside collaborator.
 	Reason string `json:"reason" yaml:"reason"`
+
+	// Sponsor is the login of the member of the organization accountable
+	// for this outside collaborator.
+	Sponsor string `json:"sponsor,omitempty" yaml:"sponsor,omitempty"`
+
+	// Expires is the last day this outside collaborator should have access
+	// to the repositories. If empty, the access doesn't expire.
+	Expires Date `json:"expires,omitempty" yaml:"expires,omitempty"`
 }
 
 type ExcludedMember struct {
@@ -386,6 +588,22 @@ type ExcludedMember struct {
 	// Reason states the reason why this user is excluded from the
 	// CodeReviewAssignment.
 	Reason string `json:"reason" yaml:"reason"`
+
+	// Until is the last day this user is excluded from the
+	// CodeReviewAssignment. If empty, the exclusion doesn't expire.
+	Until Date `json:"until,omitempty" yaml:"until,omitempty"`
+}
+
+// ActiveExcludedMembers returns the excluded members whose exclusion didn't
+// expire yet.
+func ActiveExcludedMembers(members []ExcludedMember, now time.Time) []ExcludedMember {
+	active := make([]ExcludedMember, 0, len(members))
+	for _, member := range members {
+		if !member.Until.Expired(now) {
+			active = append(active, member)
+		}
+	}
+	return active
 }
 
 type CodeReviewAssignment struct {
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+)
+
+func TestOrgRole(t *testing.T) {
+	tests := []struct {
+		graphQL        githubv4.OrganizationMemberRole
+		want           OrgRole
+		wantInvitation string
+	}{
+		{githubv4.OrganizationMemberRoleAdmin, OrgRoleAdmin, "admin"},
+		{githubv4.OrganizationMemberRoleMember, OrgRoleMember, "direct_member"},
+	}
+	for _, tt := range tests {
+		role := ParseOrgRoleFromGraphQL(tt.graphQL)
+		if role != tt.want {
+			t.Errorf("ParseOrgRoleFromGraphQL(%q) = %q, want %q", tt.graphQL, role, tt.want)
+		}
+		if got := role.InvitationRole(); got != tt.wantInvitation {
+			t.Errorf("%q.InvitationRole() = %q, want %q", role, got, tt.wantInvitation)
+		}
+	}
+	// Members without a managed role are invited as regular members.
+	if got := OrgRole("").InvitationRole(); got != "direct_member" {
+		t.Errorf(`"".InvitationRole() = %q, want "direct_member"`, got)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"sig-datapath", "sig-datapath"},
+		{"SIG Datapath", "sig-datapath"},
+		{"  ci/cd & release  ", "ci-cd-release"},
+		{"--team--", "team"},
+		{"Team_1.2", "team-1-2"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.name); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTeamSlug(t *testing.T) {
+	cfg := &Config{Teams: map[string]*TeamConfig{
+		// The slug of a renamed team is the one of its original name.
+		"SIG Datapath": {Slug: "datapath"},
+		"SIG Policy":   {},
+	}}
+	cfg.IndexTeams()
+
+	for teamName, want := range map[string]string{
+		"SIG Datapath": "datapath",
+		"SIG Policy":   "sig-policy",
+		"New Team":     "new-team",
+	} {
+		if got := cfg.TeamSlug(teamName); got != want {
+			t.Errorf("TeamSlug(%q) = %q, want %q", teamName, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of the dates in the configuration file.
+const DateLayout = time.DateOnly
+
+// Date is a calendar day, stored as "2006-01-02" in the configuration file.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of the given time.
+func NewDate(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date in the DateLayout format.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, expected format %q: %w", s, DateLayout, err)
+	}
+	return Date{t}, nil
+}
+
+// Expired returns true if the date is set and now is past the end of the day.
+func (d Date) Expired(now time.Time) bool {
+	return !d.IsZero() && !now.Before(d.AddDate(0, 0, 1))
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+func (d *Date) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+func (d *Date) parse(s string) error {
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	date, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestDateExpired(t *testing.T) {
+	date := NewDate(time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC))
+	tests := []struct {
+		date Date
+		now  time.Time
+		want bool
+	}{
+		{date, time.Date(2026, 2, 28, 23, 59, 0, 0, time.UTC), false},
+		// The date is the last day, included.
+		{date, time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC), false},
+		{date, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), true},
+		// Dates not set never expire.
+		{Date{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := tt.date.Expired(tt.now); got != tt.want {
+			t.Errorf("%q.Expired(%s) = %v, want %v", tt.date, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestDateEncoding(t *testing.T) {
+	type dates struct {
+		Until Date `json:"until,omitempty" yaml:"until,omitempty"`
+	}
+	tests := []struct {
+		name string
+		yaml string
+		json string
+		want Date
+		// wantYAML is the marshalled YAML, if different from yaml.
+		wantYAML string
+		wantErr  bool
+	}{
+		{
+			name: "date",
+			yaml: "until: \"2026-03-01\"\n",
+			json: `{"until":"2026-03-01"}`,
+			want: NewDate(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "empty",
+			yaml: "until: \"\"\n",
+			json: `{"until":""}`,
+			// Dates not set are omitted.
+			wantYAML: "{}\n",
+		},
+		{
+			name:    "invalid",
+			yaml:    "until: 01/03/2026\n",
+			json:    `{"until":"01/03/2026"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromYAML, fromJSON dates
+			errYAML := yaml.Unmarshal([]byte(tt.yaml), &fromYAML)
+			errJSON := json.Unmarshal([]byte(tt.json), &fromJSON)
+			if tt.wantErr {
+				if errYAML == nil || errJSON == nil {
+					t.Fatalf("unmarshal errors = %v, %v, want errors", errYAML, errJSON)
+				}
+				return
+			}
+			if errYAML != nil || errJSON != nil {
+				t.Fatalf("unmarshal errors = %v, %v", errYAML, errJSON)
+			}
+			if !fromYAML.Until.Equal(tt.want.Time) || !fromJSON.Until.Equal(tt.want.Time) {
+				t.Errorf("unmarshalled dates = %s, %s, want %s", fromYAML.Until, fromJSON.Until, tt.want)
+			}
+
+			wantYAML := tt.wantYAML
+			if wantYAML == "" {
+				wantYAML = tt.yaml
+			}
+			data, err := yaml.Marshal(fromYAML)
+			if err != nil || string(data) != wantYAML {
+				t.Errorf("yaml.Marshal() = %q, %v, want %q", data, err, wantYAML)
+			}
+			data, err = json.Marshal(fromJSON)
+			if err != nil || string(data) != tt.json {
+				t.Errorf("json.Marshal() = %s, %v, want %s", data, err, tt.json)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// InvitationExpiry is how long GitHub keeps an invitation to join the
+// organization before it expires. GitHub doesn't return the expiry of
+// invitations, so it's estimated from their creation date.
+const InvitationExpiry = 7 * 24 * time.Hour
+
+// Invitation is an invitation to join the organization.
+type Invitation struct {
+	// ID is the REST ID of the invitation.
+	ID int64
+	// Login of the invited user. Empty if the user was invited by email.
+	Login string
+	// Email of the invited user. Empty if the user was invited by login.
+	Email string
+	// Role is the organization role the user was invited with.
+	Role string
+	// Inviter is the login of the user who sent the invitation.
+	Inviter string
+	// CreatedAt is when the invitation was sent.
+	CreatedAt time.Time
+	// ExpiresAt is when the invitation is estimated to expire, if not
+	// accepted, based on InvitationExpiry.
+	ExpiresAt time.Time
+	// FailedAt is when the invitation failed, zero if it's still pending.
+	FailedAt time.Time
+	// FailedReason is the reason the invitation failed, for example because
+	// it expired.
+	FailedReason string
+}
+
+// Invitee returns the login of the invited user, or the email if the user was
+// invited by email.
+func (i Invitation) Invitee() string {
+	if i.Login != "" {
+		return i.Login
+	}
+	return i.Email
+}
+
+// Failed returns true if the invitation failed or expired.
+func (i Invitation) Failed() bool {
+	return !i.FailedAt.IsZero()
+}
+
+// Age returns how long ago the invitation was sent.
+func (i Invitation) Age(now time.Time) time.Duration {
+	return now.Sub(i.CreatedAt)
+}
+
+func (i Invitation) String() string {
+	s := fmt.Sprintf("invited by %s on %s", i.Inviter, i.CreatedAt.Format(time.DateOnly))
+	if i.Failed() {
+		return fmt.Sprintf("%s, failed on %s: %s", s, i.FailedAt.Format(time.DateOnly), i.FailedReason)
+	}
+	return fmt.Sprintf("%s, expires around %s (estimated)", s, i.ExpiresAt.Format(time.DateOnly))
+}
+
+// PendingInvitation returns the pending invitation of the given login, if any.
+func (c *Config) PendingInvitation(login string) (Invitation, bool) {
+	for _, invitation := range c.Invitations {
+		if invitation.Login == login && !invitation.Failed() {
+			return invitation, true
+		}
+	}
+	return Invitation{}, false
+}
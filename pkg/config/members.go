@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// RemoveMember removes the member from the organization members, all teams,
+// code review exclusions and team overrides, and clears it as the sponsor of
+// outside collaborators. The direct permissions of the member on repositories
+// are removed as well, except the ones on keepRepos. Protections are left
+// untouched, so protected members must be unprotected first. It returns a
+// description of every change done, sorted.
+func (c *Config) RemoveMember(login string, keepRepos []RepositoryName) []string {
+	var changes []string
+	if _, ok := c.Members[login]; ok {
+		delete(c.Members, login)
+		changes = append(changes, "removed from organization members")
+	}
+
+	for teamName, team := range c.AllTeams {
+		if slices.Contains(team.Members, login) {
+			team.Members = withoutLogin(team.Members, login)
+			delete(team.MembersUntil, login)
+			changes = append(changes, fmt.Sprintf("removed from members of team %q", teamName))
+		}
+		if slices.Contains(team.Mentors, login) {
+			team.Mentors = withoutLogin(team.Mentors, login)
+			changes = append(changes, fmt.Sprintf("removed from mentors of team %q", teamName))
+		}
+		if slices.Contains(team.Maintainers, login) {
+			team.Maintainers = withoutLogin(team.Maintainers, login)
+			changes = append(changes, fmt.Sprintf("removed from maintainers of team %q", teamName))
+		}
+		excluded := len(team.CodeReviewAssignment.ExcludedMembers)
+		team.CodeReviewAssignment.ExcludedMembers = slices.DeleteFunc(team.CodeReviewAssignment.ExcludedMembers, func(member ExcludedMember) bool {
+			return member.Login == login
+		})
+		if excluded != len(team.CodeReviewAssignment.ExcludedMembers) {
+			changes = append(changes, fmt.Sprintf("removed from code review exclusions of team %q", teamName))
+		}
+	}
+
+	// Overrides share the member lists with the teams they override, so they
+	// are replaced instead of modified in place.
+	for teamName, override := range c.TeamOverrides {
+		if slices.Contains(override.Members, login) {
+			override.Members = withoutLogin(override.Members, login)
+			changes = append(changes, fmt.Sprintf("removed from members override of team %q", teamName))
+		}
+		if slices.Contains(override.Mentors, login) {
+			override.Mentors = withoutLogin(override.Mentors, login)
+			changes = append(changes, fmt.Sprintf("removed from mentors override of team %q", teamName))
+		}
+	}
+
+	// Outside collaborators need a new sponsor, which 'lint' reports.
+	for collaboratorLogin, collaborator := range c.Collaborators {
+		if collaborator.Sponsor == login {
+			collaborator.Sponsor = ""
+			c.Collaborators[collaboratorLogin] = collaborator
+			changes = append(changes, fmt.Sprintf("removed as sponsor of outside collaborator %q, which needs a new sponsor", collaboratorLogin))
+		}
+	}
+
+	if slices.Contains(c.ExcludeCRAFromAllTeams, login) {
+		c.ExcludeCRAFromAllTeams = withoutLogin(c.ExcludeCRAFromAllTeams, login)
+		changes = append(changes, "removed from code review exclusions of all teams")
+	}
+
+	for repoName, repo := range c.Repositories {
+		for permission, users := range repo {
+			if !permission.IsUser() || !slices.Contains(users, TeamOrMemberName(login)) {
+				continue
+			}
+			if slices.Contains(keepRepos, repoName) {
+				changes = append(changes, fmt.Sprintf("kept %s permission on repository %q", permission.GetPermission(), repoName))
+				continue
+			}
+			repo[permission] = slices.DeleteFunc(slices.Clone(users), func(user TeamOrMemberName) bool {
+				return user == TeamOrMemberName(login)
+			})
+			if len(repo[permission]) == 0 {
+				delete(repo, permission)
+			}
+			changes = append(changes, fmt.Sprintf("removed %s permission on repository %q", permission.GetPermission(), repoName))
+		}
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// UserRepositories returns the repositories the given user has a direct
+// permission on, sorted.
+func (c *Config) UserRepositories(login string) []RepositoryName {
+	var repos []RepositoryName
+	for repoName, repo := range c.Repositories {
+		for permission, users := range repo {
+			if permission.IsUser() && slices.Contains(users, TeamOrMemberName(login)) {
+				repos = append(repos, repoName)
+				break
+			}
+		}
+	}
+	slices.Sort(repos)
+	return repos
+}
+
+func withoutLogin(logins []string, login string) []string {
+	return slices.DeleteFunc(slices.Clone(logins), func(l string) bool {
+		return l == login
+	})
+}
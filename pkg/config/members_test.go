@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name      string
+		login     string
+		keepRepos []RepositoryName
+		want      []string
+		// wantRepo is the repository "cilium" once the member is removed.
+		wantRepo Repository
+	}{
+		{
+			name:  "member everywhere",
+			login: "alice",
+			want: []string{
+				`removed ADMIN permission on repository "cilium"`,
+				`removed as sponsor of outside collaborator "eve", which needs a new sponsor`,
+				"removed from code review exclusions of all teams",
+				`removed from code review exclusions of team "parent"`,
+				`removed from maintainers of team "parent"`,
+				`removed from members of team "parent"`,
+				`removed from members override of team "parent"`,
+				`removed from mentors of team "parent"`,
+				`removed from mentors override of team "parent"`,
+				"removed from organization members",
+			},
+			wantRepo: Repository{
+				"ADMIN": {"parent"},
+				"WRITE": {"child"},
+			},
+		},
+		{
+			name:      "repository kept",
+			login:     "alice",
+			keepRepos: []RepositoryName{"cilium"},
+			want: []string{
+				`kept ADMIN permission on repository "cilium"`,
+				`removed as sponsor of outside collaborator "eve", which needs a new sponsor`,
+				"removed from code review exclusions of all teams",
+				`removed from code review exclusions of team "parent"`,
+				`removed from maintainers of team "parent"`,
+				`removed from members of team "parent"`,
+				`removed from members override of team "parent"`,
+				`removed from mentors of team "parent"`,
+				`removed from mentors override of team "parent"`,
+				"removed from organization members",
+			},
+			wantRepo: Repository{
+				"ADMIN":      {"parent"},
+				"WRITE":      {"child"},
+				"USER-ADMIN": {"alice"},
+			},
+		},
+		{
+			name:  "member of a child team",
+			login: "bob",
+			want: []string{
+				`removed from members of team "child"`,
+				"removed from organization members",
+			},
+			wantRepo: Repository{
+				"ADMIN":      {"parent"},
+				"WRITE":      {"child"},
+				"USER-ADMIN": {"alice"},
+			},
+		},
+		{
+			name:  "unknown member",
+			login: "mallory",
+			wantRepo: Repository{
+				"ADMIN":      {"parent"},
+				"WRITE":      {"child"},
+				"USER-ADMIN": {"alice"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := renameConfig()
+			got := cfg.RemoveMember(tt.login, tt.keepRepos)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveMember() =\n%q\nwant\n%q", got, tt.want)
+			}
+
+			if _, ok := cfg.Members[tt.login]; ok {
+				t.Errorf("member %q not removed", tt.login)
+			}
+			for teamName, team := range cfg.AllTeams {
+				if slices.Contains(team.Members, tt.login) || slices.Contains(team.Mentors, tt.login) || slices.Contains(team.Maintainers, tt.login) {
+					t.Errorf("member %q not removed from team %q", tt.login, teamName)
+				}
+				if _, ok := team.MembersUntil[tt.login]; ok {
+					t.Errorf("expiry of the membership of %q in team %q not removed", tt.login, teamName)
+				}
+			}
+			if !reflect.DeepEqual(cfg.Repositories["cilium"], tt.wantRepo) {
+				t.Errorf("repository = %v, want %v", cfg.Repositories["cilium"], tt.wantRepo)
+			}
+			// Protections are left to the caller.
+			if !reflect.DeepEqual(cfg.Protected.Members, []string{"alice"}) {
+				t.Errorf("protected members = %q, want %q", cfg.Protected.Members, []string{"alice"})
+			}
+		})
+	}
+}
+
+func TestUserRepositories(t *testing.T) {
+	cfg := &Config{Repositories: map[RepositoryName]Repository{
+		"cilium": {"USER-WRITE": {"alice"}, "USER-READ": {"alice"}},
+		"docs":   {"USER-ADMIN": {"bob"}},
+		// Teams named like the user don't count.
+		"hubble": {"WRITE": {"alice"}},
+		"tetra":  {"USER-READ": {"bob", "alice"}},
+	}}
+	want := []RepositoryName{"cilium", "tetra"}
+	if got := cfg.UserRepositories("alice"); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserRepositories() = %q, want %q", got, want)
+	}
+	if got := cfg.UserRepositories("carol"); len(got) != 0 {
+		t.Errorf("UserRepositories() = %q, want none", got)
+	}
+}
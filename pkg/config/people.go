@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Person is a person behind one or more GitHub accounts.
+type Person struct {
+	// Name is the real name of the person.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Email is the email address of the person.
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+
+	// EmployeeID is the ID of the person in the corporate directory.
+	EmployeeID string `json:"employeeID,omitempty" yaml:"employeeID,omitempty"`
+
+	// SlackID is the Slack user ID of the person.
+	SlackID string `json:"slackID,omitempty" yaml:"slackID,omitempty"`
+
+	// Timezone is the IANA time zone of the person, e.g. "Europe/Lisbon".
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+
+	// Manager is the ID of the person managing this person.
+	Manager string `json:"manager,omitempty" yaml:"manager,omitempty"`
+
+	// Logins are the GitHub logins of the person.
+	Logins []string `json:"logins,omitempty" yaml:"logins,omitempty"`
+
+	// Bots are the GitHub logins of the bot accounts owned by the person.
+	Bots []string `json:"bots,omitempty" yaml:"bots,omitempty"`
+}
+
+// People maps the ID of each person to their information.
+type People map[string]Person
+
+// Owner returns the ID of the person with the given GitHub login, either as
+// one of their logins or one of their bots.
+func (p People) Owner(login string) (string, bool) {
+	for _, id := range p.IDs() {
+		person := p[id]
+		if slices.Contains(person.Logins, login) || slices.Contains(person.Bots, login) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// IDs returns the IDs of the people, sorted.
+func (p People) IDs() []string {
+	ids := make([]string, 0, len(p))
+	for id := range p {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Find returns the IDs of the people identified by s, which is either their
+// ID, one of their GitHub logins, their email, employee ID or Slack ID, or a
+// part of their name.
+func (p People) Find(s string) []string {
+	var ids []string
+	for _, id := range p.IDs() {
+		person := p[id]
+		switch {
+		case id == s,
+			slices.Contains(person.Logins, s),
+			slices.Contains(person.Bots, s),
+			person.Email != "" && strings.EqualFold(person.Email, s),
+			person.EmployeeID != "" && person.EmployeeID == s,
+			person.SlackID != "" && person.SlackID == s,
+			strings.Contains(strings.ToLower(person.Name), strings.ToLower(s)):
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// checkLogins returns an error if a GitHub login belongs to more than one
+// person.
+func (p People) checkLogins() error {
+	owners := map[string]string{}
+	for _, id := range p.IDs() {
+		person := p[id]
+		for _, login := range append(slices.Clone(person.Logins), person.Bots...) {
+			if other, ok := owners[login]; ok && other != id {
+				return fmt.Errorf("login %q belongs to both people %q and %q", login, other, id)
+			}
+			owners[login] = id
+		}
+	}
+	return nil
+}
+
+// AllPeople returns the people of the configuration together with the people
+// shared with other organizations. The people of the configuration take
+// precedence.
+func (c *Config) AllPeople() People {
+	people := make(People, len(c.People)+len(c.SharedPeople))
+	for id, person := range c.SharedPeople {
+		people[id] = person
+	}
+	for id, person := range c.People {
+		people[id] = person
+	}
+	return people
+}
+
+// ApplyPeople sets the name and Slack ID of the members of the organization
+// from the people they belong to.
+func (c *Config) ApplyPeople() {
+	for _, person := range c.AllPeople() {
+		for _, login := range person.Logins {
+			member, ok := c.Members[login]
+			if !ok {
+				continue
+			}
+			if person.Name != "" {
+				member.Name = person.Name
+			}
+			if person.SlackID != "" {
+				member.SlackID = person.SlackID
+			}
+			c.Members[login] = member
+		}
+	}
+}
+
+// MemberLogins returns the logins of the person with the given ID that are
+// members of the organization, sorted.
+func (c *Config) MemberLogins(id string) []string {
+	var logins []string
+	for _, login := range c.AllPeople()[id].Logins {
+		if _, ok := c.Members[login]; ok {
+			logins = append(logins, login)
+		}
+	}
+	sort.Strings(logins)
+	return logins
+}
+
+// OrganizationFiles are the files with the configuration of an organization.
+type OrganizationFiles struct {
+	// ConfigFilename is the configuration file of the organization. Relative
+	// paths are relative to the organizations file.
+	ConfigFilename string `json:"configFilename" yaml:"configFilename"`
+
+	// OverrideFilename is the optional team override file of the
+	// organization. Relative paths are relative to the organizations file.
+	OverrideFilename string `json:"overrideFilename,omitempty" yaml:"overrideFilename,omitempty"`
+}
+
+// Organizations is the configuration of several organizations managed
+// together.
+type Organizations struct {
+	// People are the people shared by all organizations.
+	People People `json:"people,omitempty" yaml:"people,omitempty"`
+
+	// Organizations maps the name of each organization to its files.
+	Organizations map[string]OrganizationFiles `json:"organizations" yaml:"organizations"`
+}
+
+// Names returns the names of the organizations, sorted.
+func (o *Organizations) Names() []string {
+	names := make([]string, 0, len(o.Organizations))
+	for name := range o.Organizations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check checks that every organization has a configuration file and that no
+// GitHub login belongs to more than one person.
+func (o *Organizations) Check() error {
+	if len(o.Organizations) == 0 {
+		return fmt.Errorf("no organizations found")
+	}
+	for _, name := range o.Names() {
+		if o.Organizations[name].ConfigFilename == "" {
+			return fmt.Errorf("organization %q does not have a configFilename", name)
+		}
+	}
+	return o.People.checkLogins()
+}
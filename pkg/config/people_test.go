@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func peopleConfig() *Config {
+	return &Config{
+		Members: map[string]User{
+			"alice":     {ID: "U1"},
+			"alice-alt": {ID: "U2"},
+			"bob":       {ID: "U3", Name: "Robert"},
+			"bob-bot":   {ID: "U4"},
+		},
+		People: People{
+			"alice": {
+				Name:       "Alice Liddell",
+				Email:      "alice@example.com",
+				EmployeeID: "1001",
+				SlackID:    "SALICE",
+				Logins:     []string{"alice-alt", "alice", "alice-old"},
+			},
+			"bob": {
+				Name:    "Bob Builder",
+				Manager: "alice",
+				Logins:  []string{"bob"},
+				Bots:    []string{"bob-bot"},
+			},
+		},
+		SharedPeople: People{
+			"bob":   {Name: "Bob from another organization"},
+			"carol": {Name: "Carol Danvers", Logins: []string{"carol"}},
+		},
+	}
+}
+
+func TestPeopleFind(t *testing.T) {
+	people := peopleConfig().AllPeople()
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"alice", []string{"alice"}},
+		{"alice-old", []string{"alice"}},
+		{"bob-bot", []string{"bob"}},
+		{"ALICE@example.com", []string{"alice"}},
+		{"1001", []string{"alice"}},
+		{"SALICE", []string{"alice"}},
+		{"danvers", []string{"carol"}},
+		{"ER", []string{"bob", "carol"}},
+		{"dave", nil},
+	}
+	for _, tt := range tests {
+		if got := people.Find(tt.s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Find(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPeopleOwner(t *testing.T) {
+	people := peopleConfig().People
+	tests := []struct {
+		login  string
+		want   string
+		wantOK bool
+	}{
+		{"alice-alt", "alice", true},
+		{"bob-bot", "bob", true},
+		{"carol", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := people.Owner(tt.login); got != tt.want || ok != tt.wantOK {
+			t.Errorf("Owner(%q) = %q, %v, want %q, %v", tt.login, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestAllPeople(t *testing.T) {
+	cfg := peopleConfig()
+	got := cfg.AllPeople()
+	want := People{
+		"alice": cfg.People["alice"],
+		// The people of the configuration take precedence.
+		"bob":   cfg.People["bob"],
+		"carol": cfg.SharedPeople["carol"],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllPeople() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyPeople(t *testing.T) {
+	cfg := peopleConfig()
+	cfg.ApplyPeople()
+	want := map[string]User{
+		"alice":     {ID: "U1", Name: "Alice Liddell", SlackID: "SALICE"},
+		"alice-alt": {ID: "U2", Name: "Alice Liddell", SlackID: "SALICE"},
+		"bob":       {ID: "U3", Name: "Bob Builder"},
+		// Bots aren't the person.
+		"bob-bot": {ID: "U4"},
+	}
+	if !reflect.DeepEqual(cfg.Members, want) {
+		t.Errorf("members = %+v, want %+v", cfg.Members, want)
+	}
+}
+
+func TestMemberLogins(t *testing.T) {
+	cfg := peopleConfig()
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"alice", []string{"alice", "alice-alt"}},
+		{"bob", []string{"bob"}},
+		{"carol", nil},
+		{"dave", nil},
+	}
+	for _, tt := range tests {
+		if got := cfg.MemberLogins(tt.id); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MemberLogins(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePeople(t *testing.T) {
+	cfg := peopleConfig()
+	cfg.People["bob"] = Person{Manager: "zed", Logins: []string{"bob", "alice-old"}}
+	want := []Finding{
+		{
+			Rule:    RulePersonMultipleAccounts,
+			Message: `person "alice" has 2 accounts in the organization: alice, alice-alt`,
+			Path:    []string{"people", "alice", "logins"},
+		},
+		{
+			Rule:    RulePersonDuplicateLogin,
+			Message: `login "alice-old" belongs to both people "alice" and "bob"`,
+			Path:    []string{"people", "bob"},
+		},
+		{
+			Rule:    RulePersonUnknownManager,
+			Message: `manager "zed" of person "bob" does not exist`,
+			Path:    []string{"people", "bob", "manager"},
+		},
+	}
+	if got := ValidatePeople(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidatePeople() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestOrganizationsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		orgs    Organizations
+		wantErr string
+	}{
+		{
+			name: "valid",
+			orgs: Organizations{
+				People:        People{"alice": {Logins: []string{"alice"}}},
+				Organizations: map[string]OrganizationFiles{"cilium": {ConfigFilename: "cilium.yaml"}},
+			},
+		},
+		{
+			name:    "no organizations",
+			wantErr: "no organizations found",
+		},
+		{
+			name: "no configuration file",
+			orgs: Organizations{
+				Organizations: map[string]OrganizationFiles{
+					"cilium": {ConfigFilename: "cilium.yaml"},
+					"ebpf":   {OverrideFilename: "ebpf-overrides.yaml"},
+				},
+			},
+			wantErr: `organization "ebpf" does not have a configFilename`,
+		},
+		{
+			name: "duplicate login",
+			orgs: Organizations{
+				People: People{
+					"alice": {Logins: []string{"alice"}},
+					"bob":   {Logins: []string{"bob"}, Bots: []string{"alice"}},
+				},
+				Organizations: map[string]OrganizationFiles{"cilium": {ConfigFilename: "cilium.yaml"}},
+			},
+			wantErr: `login "alice" belongs to both people "alice" and "bob"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.orgs.Check()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
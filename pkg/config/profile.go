@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Profile is a named recipe to onboard new members of the organization.
+type Profile struct {
+	// Description of the profile.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Role is the organization role given to the member. If empty, the
+	// role is not managed by team-manager.
+	Role OrgRole `json:"role,omitempty" yaml:"role,omitempty"`
+
+	// Teams the member joins.
+	Teams []string `json:"teams,omitempty" yaml:"teams,omitempty"`
+
+	// Mentor makes the member a mentor of all the teams it joins.
+	Mentor bool `json:"mentor,omitempty" yaml:"mentor,omitempty"`
+
+	// ExcludeFromReviewsDays excludes the member from the code review
+	// assignment of all the teams it joins for this number of days.
+	ExcludeFromReviewsDays int `json:"excludeFromReviewsDays,omitempty" yaml:"excludeFromReviewsDays,omitempty"`
+
+	// Repositories maps the repositories the member is given a direct
+	// permission on to that permission, e.g. 'READ'.
+	Repositories map[RepositoryName]string `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+}
+
+// validRepoPermissions are the permissions that can be given on a repository.
+var validRepoPermissions = []string{"READ", "TRIAGE", "WRITE", "MAINTAIN", "ADMIN"}
+
+func (p Profile) check(cfg *Config) error {
+	switch p.Role {
+	case "", OrgRoleAdmin, OrgRoleMember:
+	default:
+		return fmt.Errorf("invalid role %q, valid roles are %q and %q", p.Role, OrgRoleAdmin, OrgRoleMember)
+	}
+	for _, teamName := range p.Teams {
+		if _, ok := cfg.AllTeams[teamName]; !ok {
+			return fmt.Errorf("team %q does not exist", teamName)
+		}
+	}
+	if p.ExcludeFromReviewsDays < 0 {
+		return fmt.Errorf("excludeFromReviewsDays can't be negative")
+	}
+	for repoName, perm := range p.Repositories {
+		if _, ok := cfg.Repositories[repoName]; !ok {
+			return fmt.Errorf("repository %q does not exist", repoName)
+		}
+		if !slices.Contains(validRepoPermissions, perm) {
+			return fmt.Errorf("invalid permission %q on repository %q, valid permissions are %q", perm, repoName, validRepoPermissions)
+		}
+	}
+	return nil
+}
+
+// Onboard applies the given profile to the member login, which must already
+// belong to the organization, and adds it to the given additional teams. It
+// returns a description of every change done. The configuration is not
+// modified if an error is returned.
+func (c *Config) Onboard(login, profileName string, teams []string, now time.Time) ([]string, error) {
+	member, ok := c.Members[login]
+	if !ok {
+		return nil, fmt.Errorf("member %q does not belong to the organization", login)
+	}
+
+	var profile Profile
+	if profileName != "" {
+		profile, ok = c.Profiles[profileName]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found", profileName)
+		}
+	}
+	profile.Teams = append(slices.Clone(profile.Teams), teams...)
+	sort.Strings(profile.Teams)
+	profile.Teams = slices.Compact(profile.Teams)
+	if err := profile.check(c); err != nil {
+		return nil, fmt.Errorf("unable to apply profile %q: %w", profileName, err)
+	}
+
+	var changes []string
+	if profile.Role != "" {
+		member.Role = profile.Role
+		c.Members[login] = member
+		changes = append(changes, fmt.Sprintf("set organization role to %s", profile.Role))
+	}
+
+	for _, teamName := range profile.Teams {
+		team := c.AllTeams[teamName]
+		if !slices.Contains(team.Members, login) {
+			team.Members = append(team.Members, login)
+			changes = append(changes, fmt.Sprintf("added to members of team %q", teamName))
+		}
+		if profile.Mentor && !slices.Contains(team.Mentors, login) {
+			team.Mentors = append(team.Mentors, login)
+			changes = append(changes, fmt.Sprintf("added to mentors of team %q", teamName))
+		}
+		if profile.ExcludeFromReviewsDays > 0 {
+			until := NewDate(now.AddDate(0, 0, profile.ExcludeFromReviewsDays))
+			team.CodeReviewAssignment.ExcludedMembers = append(
+				slices.DeleteFunc(team.CodeReviewAssignment.ExcludedMembers, func(xMember ExcludedMember) bool {
+					return xMember.Login == login
+				}),
+				ExcludedMember{
+					Login:  login,
+					Reason: fmt.Sprintf("Onboarding with profile %q", profileName),
+					Until:  until,
+				})
+			changes = append(changes, fmt.Sprintf("excluded from code review assignment of team %q until %s", teamName, until))
+		}
+	}
+
+	for repoName, perm := range profile.Repositories {
+		repo := c.Repositories[repoName]
+		// A user can only have one permission on a repository.
+		for permission, users := range repo {
+			if permission.IsUser() && slices.Contains(users, TeamOrMemberName(login)) {
+				repo[permission] = slices.DeleteFunc(slices.Clone(users), func(user TeamOrMemberName) bool {
+					return user == TeamOrMemberName(login)
+				})
+				if len(repo[permission]) == 0 {
+					delete(repo, permission)
+				}
+			}
+		}
+		permission := Permission(perm)
+		permission.SetUser()
+		repo[permission] = append(repo[permission], TeamOrMemberName(login))
+		changes = append(changes, fmt.Sprintf("given %s permission on repository %q", perm, repoName))
+	}
+
+	sort.Strings(changes)
+	return changes, nil
+}
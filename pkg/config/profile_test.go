@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func onboardConfig() *Config {
+	cfg := &Config{
+		Members: map[string]User{
+			"alice":  {ID: "U1"},
+			"newbie": {ID: "U2"},
+		},
+		Teams: map[string]*TeamConfig{
+			"sig-foo": {
+				Members: []string{"alice"},
+				CodeReviewAssignment: CodeReviewAssignment{
+					ExcludedMembers: []ExcludedMember{{Login: "newbie", Reason: "on leave"}},
+				},
+			},
+			"sig-bar": {Members: []string{"alice", "newbie"}},
+		},
+		Repositories: map[RepositoryName]Repository{
+			"cilium": {"USER-READ": {"newbie"}, "WRITE": {"sig-foo"}},
+			"docs":   {},
+		},
+		Profiles: map[string]Profile{
+			"committer": {
+				Role:                   OrgRoleMember,
+				Teams:                  []string{"sig-foo"},
+				ExcludeFromReviewsDays: 30,
+				Repositories:           map[RepositoryName]string{"cilium": "WRITE"},
+			},
+			"mentor": {
+				Teams:  []string{"sig-foo", "sig-bar"},
+				Mentor: true,
+			},
+			"broken": {
+				Repositories: map[RepositoryName]string{"docs": "OWNER"},
+			},
+		},
+	}
+	cfg.IndexTeams()
+	return cfg
+}
+
+func TestOnboard(t *testing.T) {
+	now := time.Date(2026, 1, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		login   string
+		profile string
+		teams   []string
+		want    []string
+		// wantCfg applies the changes to the configuration.
+		wantCfg func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:    "profile",
+			login:   "newbie",
+			profile: "committer",
+			want: []string{
+				`added to members of team "sig-foo"`,
+				`excluded from code review assignment of team "sig-foo" until 2026-02-09`,
+				`given WRITE permission on repository "cilium"`,
+				"set organization role to member",
+			},
+			wantCfg: func(cfg *Config) {
+				cfg.Members["newbie"] = User{ID: "U2", Role: OrgRoleMember}
+				team := cfg.Teams["sig-foo"]
+				team.Members = []string{"alice", "newbie"}
+				team.CodeReviewAssignment.ExcludedMembers = []ExcludedMember{{
+					Login:  "newbie",
+					Reason: `Onboarding with profile "committer"`,
+					Until:  NewDate(time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)),
+				}}
+				cfg.Repositories["cilium"] = Repository{"USER-WRITE": {"newbie"}, "WRITE": {"sig-foo"}}
+			},
+		},
+		{
+			name:    "mentor",
+			login:   "newbie",
+			profile: "mentor",
+			want: []string{
+				`added to members of team "sig-foo"`,
+				`added to mentors of team "sig-bar"`,
+				`added to mentors of team "sig-foo"`,
+			},
+			wantCfg: func(cfg *Config) {
+				cfg.Teams["sig-foo"].Members = []string{"alice", "newbie"}
+				cfg.Teams["sig-foo"].Mentors = []string{"newbie"}
+				cfg.Teams["sig-bar"].Mentors = []string{"newbie"}
+			},
+		},
+		{
+			name:  "additional teams without a profile",
+			login: "alice",
+			teams: []string{"sig-bar", "sig-foo", "sig-foo"},
+			// alice already belongs to both teams.
+			wantCfg: func(cfg *Config) {},
+		},
+		{
+			name:    "unknown member",
+			login:   "mallory",
+			profile: "committer",
+			wantErr: `member "mallory" does not belong to the organization`,
+		},
+		{
+			name:    "unknown profile",
+			login:   "newbie",
+			profile: "maintainer",
+			wantErr: `profile "maintainer" not found`,
+		},
+		{
+			name:    "unknown additional team",
+			login:   "newbie",
+			profile: "committer",
+			teams:   []string{"sig-ghost"},
+			wantErr: `unable to apply profile "committer": team "sig-ghost" does not exist`,
+		},
+		{
+			name:    "invalid profile",
+			login:   "newbie",
+			profile: "broken",
+			wantErr: `unable to apply profile "broken": invalid permission "OWNER" on repository "docs", valid permissions are ["READ" "TRIAGE" "WRITE" "MAINTAIN" "ADMIN"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := onboardConfig()
+			got, err := cfg.Onboard(tt.login, tt.profile, tt.teams, now)
+
+			want := onboardConfig()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Onboard() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Onboard() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Onboard() =\n%q\nwant\n%q", got, tt.want)
+				}
+				tt.wantCfg(want)
+			}
+			// The configuration isn't modified on errors.
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("configuration =\n%+v\nwant\n%+v", cfg, want)
+			}
+		})
+	}
+}
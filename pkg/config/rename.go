@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RenameTeam renames the team oldName into newName, as well as all references
+// to it from its children, repositories, protections and profiles.
+func (c *Config) RenameTeam(oldName, newName string) error {
+	team, ok := c.AllTeams[oldName]
+	if !ok {
+		return fmt.Errorf("team %q not found", oldName)
+	}
+	if _, ok := c.AllTeams[newName]; ok {
+		return fmt.Errorf("team %q already exists", newName)
+	}
+
+	siblings := c.Teams
+	if team.ParentTeam != "" {
+		parent, ok := c.AllTeams[string(team.ParentTeam)]
+		if !ok {
+			return fmt.Errorf("parent team %q of %q not found", team.ParentTeam, oldName)
+		}
+		siblings = parent.Children
+	}
+	delete(siblings, oldName)
+	siblings[newName] = team
+
+	for _, child := range team.Children {
+		child.ParentTeam = TeamOrMemberName(newName)
+	}
+
+	for _, repo := range c.Repositories {
+		for permission, teams := range repo {
+			if permission.IsUser() {
+				continue
+			}
+			if i := slices.Index(teams, TeamOrMemberName(oldName)); i != -1 {
+				teams[i] = TeamOrMemberName(newName)
+			}
+		}
+	}
+
+	if i := slices.Index(c.Protected.Teams, oldName); i != -1 {
+		c.Protected.Teams[i] = newName
+	}
+
+	for _, profile := range c.Profiles {
+		if i := slices.Index(profile.Teams, oldName); i != -1 {
+			profile.Teams[i] = newName
+		}
+	}
+
+	if override, ok := c.TeamOverrides[oldName]; ok {
+		delete(c.TeamOverrides, oldName)
+		c.TeamOverrides[newName] = override
+	}
+
+	delete(c.AllTeams, oldName)
+	c.AllTeams[newName] = team
+	return nil
+}
+
+// RenameMember renames the member oldLogin into newLogin, as well as all
+// references to it from teams, repositories, code review exclusions,
+// protections, team overrides, outside collaborator sponsors and the accounts
+// of the people of the configuration file. People shared by several
+// organizations are not renamed.
+func (c *Config) RenameMember(oldLogin, newLogin string) error {
+	user, ok := c.Members[oldLogin]
+	if !ok {
+		return fmt.Errorf("member %q not found", oldLogin)
+	}
+	if _, ok := c.Members[newLogin]; ok {
+		return fmt.Errorf("member %q already exists", newLogin)
+	}
+	delete(c.Members, oldLogin)
+	c.Members[newLogin] = user
+
+	renameLogin(c.ExcludeCRAFromAllTeams, oldLogin, newLogin)
+	renameLogin(c.Protected.Members, oldLogin, newLogin)
+
+	for _, person := range c.People {
+		renameLogin(person.Logins, oldLogin, newLogin)
+		renameLogin(person.Bots, oldLogin, newLogin)
+	}
+
+	for login, collaborator := range c.Collaborators {
+		if collaborator.Sponsor == oldLogin {
+			collaborator.Sponsor = newLogin
+			c.Collaborators[login] = collaborator
+		}
+	}
+
+	for _, team := range c.AllTeams {
+		renameLogin(team.Members, oldLogin, newLogin)
+		renameLogin(team.Mentors, oldLogin, newLogin)
+		renameLogin(team.Maintainers, oldLogin, newLogin)
+		if until, ok := team.MembersUntil[oldLogin]; ok {
+			delete(team.MembersUntil, oldLogin)
+			team.MembersUntil[newLogin] = until
+		}
+		for i, excluded := range team.CodeReviewAssignment.ExcludedMembers {
+			if excluded.Login == oldLogin {
+				team.CodeReviewAssignment.ExcludedMembers[i].Login = newLogin
+			}
+		}
+	}
+
+	for _, override := range c.TeamOverrides {
+		renameLogin(override.Members, oldLogin, newLogin)
+		renameLogin(override.Mentors, oldLogin, newLogin)
+	}
+
+	for _, repo := range c.Repositories {
+		for permission, users := range repo {
+			if !permission.IsUser() {
+				continue
+			}
+			if i := slices.Index(users, TeamOrMemberName(oldLogin)); i != -1 {
+				users[i] = TeamOrMemberName(newLogin)
+			}
+		}
+	}
+	return nil
+}
+
+func renameLogin(logins []string, oldLogin, newLogin string) {
+	for i, login := range logins {
+		if login == oldLogin {
+			logins[i] = newLogin
+		}
+	}
+}
+
+// LoginRenames returns the members of the local configuration that were
+// renamed in GitHub, mapping their local login to their upstream login.
+// Renames are detected by the ID of the members, which doesn't change when a
+// GitHub account is renamed.
+func LoginRenames(localCfg, upstreamCfg *Config) map[string]string {
+	upstreamLogins := make(map[string]string, len(upstreamCfg.Members))
+	for login, user := range upstreamCfg.Members {
+		if user.ID != "" {
+			upstreamLogins[user.ID] = login
+		}
+	}
+
+	renames := map[string]string{}
+	for login, user := range localCfg.Members {
+		if user.ID == "" {
+			continue
+		}
+		if _, ok := upstreamCfg.Members[login]; ok {
+			continue
+		}
+		upstreamLogin, ok := upstreamLogins[user.ID]
+		if !ok {
+			continue
+		}
+		if _, ok := localCfg.Members[upstreamLogin]; ok {
+			continue
+		}
+		renames[login] = upstreamLogin
+	}
+	return renames
+}
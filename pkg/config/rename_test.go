@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func renameConfig() *Config {
+	until := NewDate(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC))
+	cfg := &Config{
+		Members: map[string]User{
+			"alice": {ID: "U1"},
+			"bob":   {ID: "U2"},
+		},
+		ExcludeCRAFromAllTeams: []string{"alice"},
+		Protected: Protected{
+			Members: []string{"alice"},
+			Teams:   []string{"parent"},
+		},
+		People: People{
+			"alice": {Logins: []string{"alice"}, Bots: []string{"bob"}},
+		},
+		Collaborators: map[string]OutsideCollaborator{
+			"eve": {Reason: "contractor", Sponsor: "alice"},
+		},
+		Teams: map[string]*TeamConfig{
+			"parent": {
+				ID:           "T1",
+				Members:      []string{"alice", "bob"},
+				Maintainers:  []string{"alice"},
+				Mentors:      []string{"alice"},
+				MembersUntil: map[string]Date{"alice": until},
+				CodeReviewAssignment: CodeReviewAssignment{
+					ExcludedMembers: []ExcludedMember{{Login: "alice", Reason: "on leave"}},
+				},
+				Children: map[string]*TeamConfig{
+					"child": {ID: "T2", ParentTeam: "parent", Members: []string{"bob"}},
+				},
+			},
+		},
+		Repositories: map[RepositoryName]Repository{
+			"cilium": {
+				"ADMIN":      {"parent"},
+				"WRITE":      {"child"},
+				"USER-ADMIN": {"alice"},
+			},
+		},
+		Profiles: map[string]Profile{
+			"committer": {Teams: []string{"parent", "child"}},
+		},
+		TeamOverrides: map[string]*OverrideTeamConfig{
+			"parent": {Members: []string{"alice"}, Mentors: []string{"alice"}},
+		},
+	}
+	cfg.IndexTeams()
+	return cfg
+}
+
+func TestRenameTeam(t *testing.T) {
+	tests := []struct {
+		name             string
+		oldName, newName string
+		// want applies the rename to the configuration.
+		want    func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:    "top-level team",
+			oldName: "parent",
+			newName: "maintainers",
+			want: func(cfg *Config) {
+				cfg.Teams["maintainers"] = cfg.Teams["parent"]
+				delete(cfg.Teams, "parent")
+				cfg.Teams["maintainers"].Children["child"].ParentTeam = "maintainers"
+				cfg.Repositories["cilium"]["ADMIN"] = []TeamOrMemberName{"maintainers"}
+				cfg.Protected.Teams = []string{"maintainers"}
+				cfg.Profiles["committer"] = Profile{Teams: []string{"maintainers", "child"}}
+				cfg.TeamOverrides["maintainers"] = cfg.TeamOverrides["parent"]
+				delete(cfg.TeamOverrides, "parent")
+			},
+		},
+		{
+			name:    "child team",
+			oldName: "child",
+			newName: "kid",
+			want: func(cfg *Config) {
+				children := cfg.Teams["parent"].Children
+				children["kid"] = children["child"]
+				delete(children, "child")
+				cfg.Repositories["cilium"]["WRITE"] = []TeamOrMemberName{"kid"}
+				cfg.Profiles["committer"] = Profile{Teams: []string{"parent", "kid"}}
+			},
+		},
+		{
+			name:    "unknown team",
+			oldName: "ops",
+			newName: "sre",
+			wantErr: `team "ops" not found`,
+		},
+		{
+			name:    "existing team",
+			oldName: "child",
+			newName: "parent",
+			wantErr: `team "parent" already exists`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := renameConfig()
+			err := cfg.RenameTeam(tt.oldName, tt.newName)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("RenameTeam() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenameTeam() error = %v", err)
+			}
+
+			want := renameConfig()
+			tt.want(want)
+			want.IndexTeams()
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("RenameTeam() =\n%+v\nwant\n%+v", cfg, want)
+			}
+		})
+	}
+}
+
+func TestRenameMember(t *testing.T) {
+	cfg := renameConfig()
+	if err := cfg.RenameMember("alice", "alice-gh"); err != nil {
+		t.Fatalf("RenameMember() error = %v", err)
+	}
+
+	want := renameConfig()
+	want.Members["alice-gh"] = want.Members["alice"]
+	delete(want.Members, "alice")
+	want.ExcludeCRAFromAllTeams = []string{"alice-gh"}
+	want.Protected.Members = []string{"alice-gh"}
+	want.People["alice"] = Person{Logins: []string{"alice-gh"}, Bots: []string{"bob"}}
+	want.Collaborators["eve"] = OutsideCollaborator{Reason: "contractor", Sponsor: "alice-gh"}
+	parent := want.Teams["parent"]
+	parent.Members = []string{"alice-gh", "bob"}
+	parent.Maintainers = []string{"alice-gh"}
+	parent.Mentors = []string{"alice-gh"}
+	parent.MembersUntil = map[string]Date{"alice-gh": parent.MembersUntil["alice"]}
+	parent.CodeReviewAssignment.ExcludedMembers[0].Login = "alice-gh"
+	want.TeamOverrides["parent"] = &OverrideTeamConfig{Members: []string{"alice-gh"}, Mentors: []string{"alice-gh"}}
+	want.Repositories["cilium"]["USER-ADMIN"] = []TeamOrMemberName{"alice-gh"}
+	want.IndexTeams()
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("RenameMember() =\n%+v\nwant\n%+v", cfg, want)
+	}
+
+	for _, tt := range []struct {
+		oldLogin, newLogin string
+		wantErr            string
+	}{
+		{"alice", "carol", `member "alice" not found`},
+		{"bob", "alice-gh", `member "alice-gh" already exists`},
+	} {
+		if err := cfg.RenameMember(tt.oldLogin, tt.newLogin); err == nil || err.Error() != tt.wantErr {
+			t.Errorf("RenameMember(%q, %q) error = %v, want %q", tt.oldLogin, tt.newLogin, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoginRenames(t *testing.T) {
+	tests := []struct {
+		name            string
+		local, upstream map[string]User
+		want            map[string]string
+	}{
+		{
+			name:     "no renames",
+			local:    map[string]User{"alice": {ID: "U1"}},
+			upstream: map[string]User{"alice": {ID: "U1"}},
+			want:     map[string]string{},
+		},
+		{
+			name:     "renamed",
+			local:    map[string]User{"alice": {ID: "U1"}, "bob": {ID: "U2"}},
+			upstream: map[string]User{"alice-gh": {ID: "U1"}, "bob": {ID: "U2"}},
+			want:     map[string]string{"alice": "alice-gh"},
+		},
+		{
+			name:     "new member without an ID",
+			local:    map[string]User{"alice": {}},
+			upstream: map[string]User{"alice-gh": {ID: "U1"}},
+			want:     map[string]string{},
+		},
+		{
+			name:     "removed member",
+			local:    map[string]User{"alice": {ID: "U1"}},
+			upstream: map[string]User{},
+			want:     map[string]string{},
+		},
+		{
+			name:     "new login already in the local configuration",
+			local:    map[string]User{"alice": {ID: "U1"}, "alice-gh": {}},
+			upstream: map[string]User{"alice-gh": {ID: "U1"}},
+			want:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LoginRenames(&Config{Members: tt.local}, &Config{Members: tt.upstream})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoginRenames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
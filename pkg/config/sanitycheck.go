@@ -15,40 +15,34 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-
-	"github.com/shurcooL/githubv4"
+	"sort"
+	"strings"
 )
 
-// SanityCheck checks if the all team members belong to the organization.
+// SanityCheck checks the referential integrity of the configuration, e.g. if
+// all team members belong to the organization. It returns all problems found.
 func SanityCheck(cfg *Config) error {
-	// Check if all users in the CodeReviewAssignment belong to the list of
-	// members
-	for teamName, team := range cfg.AllTeams {
-		for _, member := range team.Members {
-			if _, ok := cfg.Members[member]; !ok {
-				return fmt.Errorf("member %q from team %q does not belong to organization", member, teamName)
-			}
-		}
-		for _, mentor := range team.Mentors {
-			if _, ok := cfg.Members[mentor]; !ok {
-				return fmt.Errorf("mentor %q from team %q does not belong to organization", mentor, teamName)
-			}
-		}
-		for _, xMember := range team.CodeReviewAssignment.ExcludedMembers {
-			if _, ok := cfg.Members[xMember.Login]; !ok {
-				return fmt.Errorf("member %q from code review assignment of team %q does not belong to organization", xMember.Login, teamName)
-			}
-		}
+	var errs []error
+	for _, finding := range Validate(cfg) {
+		errs = append(errs, errors.New(finding.Message))
+	}
+	return errors.Join(errs...)
+}
 
-		if team.ParentTeam != "" && githubv4.TeamPrivacy(team.Privacy) == githubv4.TeamPrivacySecret {
-			return fmt.Errorf("error in team %q: child teams can't be secret", teamName)
+// CheckMaxOwners checks that the organization doesn't have more than max
+// owners.
+func CheckMaxOwners(cfg *Config, max int) error {
+	var owners []string
+	for login, member := range cfg.Members {
+		if member.Role == OrgRoleAdmin {
+			owners = append(owners, login)
 		}
 	}
-	for _, xMember := range cfg.ExcludeCRAFromAllTeams {
-		if _, ok := cfg.Members[xMember]; !ok {
-			return fmt.Errorf("member %q from globally excluded reviews, does not belong to the organization", xMember)
-		}
+	if len(owners) > max {
+		sort.Strings(owners)
+		return fmt.Errorf("organization has %d owners (%s), more than the maximum of %d", len(owners), strings.Join(owners, ", "), max)
 	}
 	return nil
 }
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"testing"
+)
+
+func TestCheckMaxOwners(t *testing.T) {
+	members := map[string]User{
+		"alice": {Role: OrgRoleAdmin},
+		"bob":   {Role: OrgRoleAdmin},
+		"carol": {Role: OrgRoleMember},
+		// Members without a managed role aren't counted as owners.
+		"dave": {},
+	}
+	tests := []struct {
+		max     int
+		wantErr string
+	}{
+		{max: 3},
+		{max: 2},
+		{max: 1, wantErr: "organization has 2 owners (alice, bob), more than the maximum of 1"},
+	}
+	for _, tt := range tests {
+		err := CheckMaxOwners(&Config{Members: members}, tt.max)
+		var got string
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.wantErr {
+			t.Errorf("CheckMaxOwners(%d) error = %q, want %q", tt.max, got, tt.wantErr)
+		}
+	}
+}
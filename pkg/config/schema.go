@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// SchemaURI is the version of JSON Schema of the generated schemas.
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema used to describe the configuration
+// files.
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+
+	Type    string    `json:"type,omitempty"`
+	Enum    []string  `json:"enum,omitempty"`
+	Pattern string    `json:"pattern,omitempty"`
+	OneOf   []*Schema `json:"oneOf,omitempty"`
+
+	// Object keywords. AdditionalProperties is either false or a *Schema.
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+
+	// Array keywords.
+	Items *Schema `json:"items,omitempty"`
+}
+
+// ConfigSchema returns the JSON Schema of the configuration file.
+func ConfigSchema() *Schema {
+	return newSchemaGenerator().root(reflect.TypeOf(Config{}))
+}
+
+// OverrideSchema returns the JSON Schema of the override file.
+func OverrideSchema() *Schema {
+	return newSchemaGenerator().root(reflect.TypeOf(OverrideConfig{}))
+}
+
+// schemaGenerator generates schemas from the yaml tags of the configuration
+// types. Named structs are stored in the definitions of the root schema.
+type schemaGenerator struct {
+	defs map[string]*Schema
+
+	// types with a hand written schema, because they are enums or have a
+	// custom YAML representation.
+	types map[reflect.Type]func() *Schema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	g := &schemaGenerator{defs: map[string]*Schema{}}
+	permissions := []string{"ADMIN", "MAINTAIN", "WRITE", "TRIAGE", "READ"}
+	for _, perm := range permissions {
+		permissions = append(permissions, "USER-"+perm)
+	}
+	g.types = map[reflect.Type]func() *Schema{
+		reflect.TypeOf(Permission("")): func() *Schema {
+			return &Schema{Type: "string", Enum: permissions}
+		},
+		reflect.TypeOf(TeamPrivacy("")): func() *Schema {
+			return &Schema{Type: "string", Enum: []string{
+				string(githubv4.TeamPrivacySecret),
+				string(githubv4.TeamPrivacyVisible),
+			}}
+		},
+		reflect.TypeOf(TeamReviewAssignmentAlgorithm("")): func() *Schema {
+			return &Schema{Type: "string", Enum: []string{
+				string(TeamReviewAssignmentAlgorithmLoadBalance),
+				string(TeamReviewAssignmentAlgorithmRoundRobin),
+			}}
+		},
+		reflect.TypeOf(OrgRole("")): func() *Schema {
+			return &Schema{Type: "string", Enum: []string{string(OrgRoleAdmin), string(OrgRoleMember)}}
+		},
+		reflect.TypeOf(Date{}): func() *Schema {
+			return &Schema{Type: "string", Pattern: `^[0-9]{4}-[0-9]{2}-[0-9]{2}$`}
+		},
+		reflect.TypeOf(TeamMember{}): func() *Schema {
+			return &Schema{OneOf: []*Schema{
+				{Type: "string"},
+				g.define("TeamMember", reflect.TypeOf(TeamMember{})),
+			}}
+		},
+		// Teams are stored with their YAML representation.
+		reflect.TypeOf(TeamConfig{}): func() *Schema {
+			return g.define("TeamConfig", teamConfigYAML)
+		},
+	}
+	return g
+}
+
+func (g *schemaGenerator) root(t reflect.Type) *Schema {
+	g.schema(t)
+	// The root struct is inlined instead of being referenced.
+	s := g.defs[t.Name()]
+	delete(g.defs, t.Name())
+	s.Schema = SchemaURI
+	s.Defs = g.defs
+	return s
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if custom, ok := g.types[t]; ok {
+		return custom()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.define(t.Name(), t)
+	case reflect.Map:
+		s := &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+		if keys := g.schema(t.Key()); len(keys.Enum) != 0 {
+			s.PropertyNames = keys
+		}
+		return s
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// define adds the schema of the given struct to the definitions, if not
+// defined yet, and returns a reference to it.
+func (g *schemaGenerator) define(name string, t reflect.Type) *Schema {
+	ref := &Schema{Ref: "#/$defs/" + name}
+	if _, ok := g.defs[name]; ok {
+		return ref
+	}
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	// Set before generating the fields to support recursive types.
+	g.defs[name] = s
+	g.addFields(s, t)
+	return ref
+}
+
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "inline" {
+			g.addFields(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = g.schema(field.Type)
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+type schemaItem struct {
+	Name string      `yaml:"name"`
+	Next *schemaItem `yaml:"next,omitempty"`
+}
+
+type schemaEmbedded struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+type schemaRoot struct {
+	Count       int                       `yaml:"count,omitempty"`
+	Until       Date                      `yaml:"until,omitempty"`
+	Role        OrgRole                   `yaml:"role,omitempty"`
+	Items       []schemaItem              `yaml:"items,omitempty"`
+	Permissions map[Permission][]string   `yaml:"permissions,omitempty"`
+	Repos       map[RepositoryName]string `yaml:"repos,omitempty"`
+	Ignored     string                    `yaml:"-"`
+	Untagged    string
+	unexported  string
+	Embedded    schemaEmbedded `yaml:",inline"`
+}
+
+func TestSchema(t *testing.T) {
+	got := newSchemaGenerator().root(reflect.TypeOf(schemaRoot{}))
+	want := &Schema{
+		Schema: SchemaURI,
+		Type:   "object",
+		Properties: map[string]*Schema{
+			"count": {Type: "integer"},
+			"until": {Type: "string", Pattern: `^[0-9]{4}-[0-9]{2}-[0-9]{2}$`},
+			"role":  {Type: "string", Enum: []string{"admin", "member"}},
+			"items": {Type: "array", Items: &Schema{Ref: "#/$defs/schemaItem"}},
+			"permissions": {
+				Type: "object",
+				PropertyNames: &Schema{Type: "string", Enum: []string{
+					"ADMIN", "MAINTAIN", "WRITE", "TRIAGE", "READ",
+					"USER-ADMIN", "USER-MAINTAIN", "USER-WRITE", "USER-TRIAGE", "USER-READ",
+				}},
+				AdditionalProperties: &Schema{Type: "array", Items: &Schema{Type: "string"}},
+			},
+			"repos":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"untagged": {Type: "string"},
+			"enabled":  {Type: "boolean"},
+		},
+		AdditionalProperties: false,
+		Defs: map[string]*Schema{
+			"schemaItem": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"name": {Type: "string"},
+					"next": {Ref: "#/$defs/schemaItem"},
+				},
+				AdditionalProperties: false,
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	schema := ConfigSchema()
+	for _, property := range []string{"version", "organization", "members", "teams", "repositories"} {
+		if _, ok := schema.Properties[property]; !ok {
+			t.Errorf("property %q not found", property)
+		}
+	}
+	// Teams are described by their YAML representation, where members can
+	// have an expiry.
+	team, ok := schema.Defs["TeamConfig"]
+	if !ok {
+		t.Fatalf("TeamConfig not defined")
+	}
+	want := &Schema{Type: "array", Items: &Schema{OneOf: []*Schema{
+		{Type: "string"},
+		{Ref: "#/$defs/TeamMember"},
+	}}}
+	if got := team.Properties["members"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("members of TeamConfig = %+v, want %+v", got, want)
+	}
+	if _, ok := team.Properties["membersUntil"]; ok {
+		t.Errorf("MembersUntil of TeamConfig found in the schema")
+	}
+	for _, def := range []string{"TeamMember", "User"} {
+		if _, ok := schema.Defs[def]; !ok {
+			t.Errorf("%s not defined", def)
+		}
+	}
+
+	override := OverrideSchema()
+	if _, ok := override.Properties["teams"]; !ok {
+		t.Errorf("property teams not found in the override schema")
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"sort"
 
 	"github.com/shurcooL/githubv4"
+
+	"github.com/cilium/team-manager/pkg/stringset"
 )
 
 func sortMembers(team *TeamConfig) {
@@ -34,6 +36,16 @@ func sortMembers(team *TeamConfig) {
 	}
 	sort.Strings(team.Members)
 
+	// Remove the expiry of the users that are no longer members
+	for login := range team.MembersUntil {
+		if _, ok := teamMembers[login]; !ok {
+			delete(team.MembersUntil, login)
+		}
+	}
+	if len(team.MembersUntil) == 0 {
+		team.MembersUntil = nil
+	}
+
 	// Remove and sort and duplicated team mentors
 	teamMentors := make(map[string]struct{}, len(team.Mentors))
 	for _, teamMentor := range team.Mentors {
@@ -46,12 +58,25 @@ func sortMembers(team *TeamConfig) {
 	}
 	sort.Strings(team.Mentors)
 
+	// Remove and sort and duplicated team maintainers
+	team.Maintainers = stringset.New(team.Maintainers...).Elements()
+
 	for _, child := range team.Children {
 		sortMembers(child)
 	}
 }
 
-func SortConfig(cfg *Config) {
+// SortConfig sorts and deduplicates the teams, members and repository
+// permissions of cfg. It fails if a repository grants permissions to a team
+// that doesn't exist.
+func SortConfig(cfg *Config) error {
+	// Remove and sort duplicated members of the overrides, since they are
+	// applied again to the teams every time the teams are indexed.
+	for _, override := range cfg.TeamOverrides {
+		override.Members = stringset.New(override.Members...).Elements()
+		override.Mentors = stringset.New(override.Mentors...).Elements()
+	}
+
 	// Index all teams in a single map.
 	cfg.IndexTeams()
 
@@ -86,6 +111,19 @@ func SortConfig(cfg *Config) {
 	// Sort excluded team members
 	sort.Strings(cfg.ExcludeCRAFromAllTeams)
 
+	// Sort protections
+	sort.Strings(cfg.Protected.Members)
+	sort.Strings(cfg.Protected.Teams)
+	sort.Slice(cfg.Protected.Repositories, func(i, j int) bool {
+		return cfg.Protected.Repositories[i] < cfg.Protected.Repositories[j]
+	})
+
+	// Sort profiles
+	for profileName, profile := range cfg.Profiles {
+		sort.Strings(profile.Teams)
+		cfg.Profiles[profileName] = profile
+	}
+
 	// Set the right children of the parent teams
 	SetParents(cfg)
 
@@ -103,7 +141,7 @@ func SortConfig(cfg *Config) {
 				for permMember := range permMembers {
 					team := cfg.AllTeams[string(permMember)]
 					if team == nil {
-						panic(fmt.Sprintf("Couldn't find team %q", string(permMember)))
+						return fmt.Errorf("repository %q: team %q not found", repoName, permMember)
 					}
 					descendents := team.Descendents()
 					for _, descendent := range descendents {
@@ -157,6 +195,7 @@ func SortConfig(cfg *Config) {
 			cfg.Collaborators[collaborator] = OutsideCollaborator{}
 		}
 	}
+	return nil
 }
 
 func SetParents(localCfg *Config) {
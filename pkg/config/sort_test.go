@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories map[RepositoryName]Repository
+		want         map[RepositoryName]Repository
+		wantErr      string
+	}{
+		{
+			name: "sorted and deduplicated",
+			repositories: map[RepositoryName]Repository{
+				"cilium": {
+					"WRITE":      {"parent", "child", "parent"},
+					"READ":       {"child"},
+					"USER-WRITE": {"eve", "bob", "eve"},
+				},
+			},
+			want: map[RepositoryName]Repository{
+				"cilium": {
+					"WRITE":      {"parent"},
+					"READ":       {"child"},
+					"USER-WRITE": {"bob", "eve"},
+				},
+			},
+		},
+		{
+			name: "unknown team",
+			repositories: map[RepositoryName]Repository{
+				"cilium": {"WRITE": {"parent", "ghost"}},
+			},
+			wantErr: `repository "cilium": team "ghost" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := renameConfig()
+			cfg.Repositories = tt.repositories
+			err := SortConfig(cfg)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SortConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Repositories, tt.want) {
+				t.Errorf("repositories = %v, want %v", cfg.Repositories, tt.want)
+			}
+		})
+	}
+}
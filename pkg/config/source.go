@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupSource is an external group of people, such as a group of the
+// corporate directory, whose members are the members of a team. Exactly one of
+// CSV, LDIF and LDAP must be set.
+type GroupSource struct {
+	// CSV is a CSV file with a header row. Each row is a member of the group
+	// named in the 'group' column, identified by the 'email', 'employeeID'
+	// or 'login' columns.
+	CSV string `json:"csv,omitempty" yaml:"csv,omitempty"`
+
+	// LDIF is an LDIF export of a directory, where the group entry lists the
+	// DNs of its members in 'member' or 'uniqueMember' attributes, or their
+	// user IDs in 'memberUid' attributes.
+	LDIF string `json:"ldif,omitempty" yaml:"ldif,omitempty"`
+
+	// LDAP is the URL of an LDAP server, e.g. "ldaps://ldap.example.com",
+	// with the same group entries as LDIF. The credentials to bind to the
+	// server are read from the LDAP_BIND_DN and LDAP_BIND_PASSWORD
+	// environment variables, if set.
+	LDAP string `json:"ldap,omitempty" yaml:"ldap,omitempty"`
+
+	// Group is the name of the group in the CSV file, or the DN of the group
+	// entry in the LDIF file or LDAP server.
+	Group string `json:"group" yaml:"group"`
+}
+
+func (s *GroupSource) check() error {
+	var kinds []string
+	for kind, value := range map[string]string{"csv": s.CSV, "ldif": s.LDIF, "ldap": s.LDAP} {
+		if value != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	switch {
+	case len(kinds) == 0:
+		return fmt.Errorf("one of csv, ldif or ldap must be set")
+	case len(kinds) > 1:
+		return fmt.Errorf("only one of csv, ldif or ldap can be set")
+	case s.Group == "":
+		return fmt.Errorf("group must be set")
+	}
+	return nil
+}
+
+// String returns a short description of the source.
+func (s *GroupSource) String() string {
+	switch {
+	case s.CSV != "":
+		return fmt.Sprintf("group %q of %s", s.Group, s.CSV)
+	case s.LDIF != "":
+		return fmt.Sprintf("group %q of %s", s.Group, s.LDIF)
+	default:
+		return fmt.Sprintf("group %q of %s", s.Group, s.LDAP)
+	}
+}
+
+// Identity identifies a person of an external group. Empty fields are
+// unknown.
+type Identity struct {
+	// ID is the ID of the person in the people registry, or their user ID
+	// in the directory.
+	ID         string
+	Email      string
+	EmployeeID string
+	Login      string
+}
+
+func (i Identity) String() string {
+	for _, s := range []string{i.ID, i.Email, i.EmployeeID, i.Login} {
+		if s != "" {
+			return s
+		}
+	}
+	return "<unknown>"
+}
+
+// Resolve returns the ID of the person with the given identity, which is
+// matched against the ID, email, employee ID and GitHub logins of the people,
+// in this order.
+func (p People) Resolve(identity Identity) (string, bool) {
+	if _, ok := p[identity.ID]; identity.ID != "" && ok {
+		return identity.ID, true
+	}
+	for _, id := range p.IDs() {
+		person := p[id]
+		if identity.Email != "" && strings.EqualFold(person.Email, identity.Email) {
+			return id, true
+		}
+		if identity.EmployeeID != "" && person.EmployeeID == identity.EmployeeID {
+			return id, true
+		}
+	}
+	if identity.Login != "" {
+		return p.Owner(identity.Login)
+	}
+	return "", false
+}
+
+// ResolveLogins returns the logins of the members of the organization with
+// the given identities, sorted, and the identities that could not be mapped
+// to any member. Identities with a GitHub login that is not in the people
+// registry are mapped to that login if it is a member.
+func (c *Config) ResolveLogins(identities []Identity) (logins []string, unmapped []Identity) {
+	people := c.AllPeople()
+	found := map[string]struct{}{}
+	for _, identity := range identities {
+		var memberLogins []string
+		if id, ok := people.Resolve(identity); ok {
+			memberLogins = c.MemberLogins(id)
+		} else if _, ok := c.Members[identity.Login]; identity.Login != "" && ok {
+			memberLogins = []string{identity.Login}
+		}
+		if len(memberLogins) == 0 {
+			unmapped = append(unmapped, identity)
+			continue
+		}
+		for _, login := range memberLogins {
+			found[login] = struct{}{}
+		}
+	}
+	logins = make([]string, 0, len(found))
+	for login := range found {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return logins, unmapped
+}
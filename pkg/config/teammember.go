@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+)
+
+// TeamMember is a member of a team as written in the configuration file,
+// either as a plain login or as a login with the last day of its membership.
+type TeamMember struct {
+	Login string `yaml:"login"`
+	Until Date   `yaml:"until,omitempty"`
+}
+
+func (m TeamMember) MarshalYAML() (interface{}, error) {
+	if m.Until.IsZero() {
+		return m.Login, nil
+	}
+	type plain TeamMember
+	return plain(m), nil
+}
+
+func (m *TeamMember) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var login string
+	if err := unmarshal(&login); err == nil {
+		*m = TeamMember{Login: login}
+		return nil
+	}
+	type plain TeamMember
+	if err := unmarshal((*plain)(m)); err != nil {
+		return err
+	}
+	if m.Login == "" {
+		return fmt.Errorf("team member without login")
+	}
+	return nil
+}
+
+// teamConfigYAML is the representation of TeamConfig in the configuration
+// file: the same fields, except for the members, which are stored along with
+// the expiry of their membership. yaml.v2 refuses to inline an alias of
+// TeamConfig next to a second members field, so the type is derived from
+// TeamConfig instead of repeating its fields.
+var teamConfigYAML = func() reflect.Type {
+	t := reflect.TypeOf(TeamConfig{})
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Name == "Members" {
+			field.Type = reflect.TypeOf([]TeamMember{})
+		}
+		fields = append(fields, field)
+	}
+	return reflect.StructOf(fields)
+}()
+
+func (c TeamConfig) MarshalYAML() (interface{}, error) {
+	type plain TeamConfig
+	if len(c.MembersUntil) == 0 {
+		return plain(c), nil
+	}
+
+	members := make([]TeamMember, 0, len(c.Members))
+	for _, login := range c.Members {
+		members = append(members, TeamMember{Login: login, Until: c.MembersUntil[login]})
+	}
+	t := reflect.New(teamConfigYAML).Elem()
+	v := reflect.ValueOf(c)
+	for i := 0; i < t.NumField(); i++ {
+		if teamConfigYAML.Field(i).Name == "Members" {
+			t.Field(i).Set(reflect.ValueOf(members))
+			continue
+		}
+		t.Field(i).Set(v.Field(i))
+	}
+	return t.Interface(), nil
+}
+
+func (c *TeamConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	t := reflect.New(teamConfigYAML)
+	if err := unmarshal(t.Interface()); err != nil {
+		return err
+	}
+	*c = TeamConfig{}
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < teamConfigYAML.NumField(); i++ {
+		if teamConfigYAML.Field(i).Name != "Members" {
+			v.Field(i).Set(t.Elem().Field(i))
+		}
+	}
+	for _, member := range t.Elem().FieldByName("Members").Interface().([]TeamMember) {
+		c.Members = append(c.Members, member.Login)
+		if member.Until.IsZero() {
+			continue
+		}
+		if c.MembersUntil == nil {
+			c.MembersUntil = map[string]Date{}
+		}
+		c.MembersUntil[member.Login] = member.Until
+	}
+	return nil
+}
+
+// MembershipExpired returns true if the membership of the given user in the
+// team expired.
+func (c *TeamConfig) MembershipExpired(login string, now time.Time) bool {
+	return c.MembersUntil[login].Expired(now)
+}
+
+// ActiveMembers returns the members of the team whose membership didn't
+// expire yet.
+func (c *TeamConfig) ActiveMembers(now time.Time) []string {
+	return slices.DeleteFunc(slices.Clone(c.Members), func(login string) bool {
+		return c.MembershipExpired(login, now)
+	})
+}
+
+// ActiveMaintainers returns the maintainers of the team whose membership
+// didn't expire yet.
+func (c *TeamConfig) ActiveMaintainers(now time.Time) []string {
+	return slices.DeleteFunc(slices.Clone(c.Maintainers), func(login string) bool {
+		return c.MembershipExpired(login, now)
+	})
+}
+
+// MembershipExpiry is the last day of the membership of a user in a team.
+type MembershipExpiry struct {
+	Team  string
+	Login string
+	Until Date
+}
+
+func (m MembershipExpiry) String() string {
+	return fmt.Sprintf("membership of %q in team %q ends on %s", m.Login, m.Team, m.Until)
+}
+
+// ExpiringMemberships returns the team memberships that expired, or expire
+// before the given deadline, sorted by expiry.
+func ExpiringMemberships(cfg *Config, deadline time.Time) []MembershipExpiry {
+	var expiries []MembershipExpiry
+	for teamName, team := range cfg.AllTeams {
+		for login, until := range team.MembersUntil {
+			if !until.Before(deadline) || !slices.Contains(team.Members, login) {
+				continue
+			}
+			expiries = append(expiries, MembershipExpiry{Team: teamName, Login: login, Until: until})
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool {
+		if !expiries[i].Until.Equal(expiries[j].Until.Time) {
+			return expiries[i].Until.Before(expiries[j].Until.Time)
+		}
+		if expiries[i].Team != expiries[j].Team {
+			return expiries[i].Team < expiries[j].Team
+		}
+		return expiries[i].Login < expiries[j].Login
+	})
+	return expiries
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Rule IDs of the findings of Validate. They are stable and can be used to
+// filter or annotate findings.
+const (
+	RuleTeamMemberNotInOrg       = "team-member-not-in-org"
+	RuleTeamMentorNotInOrg       = "team-mentor-not-in-org"
+	RuleTeamMaintainerNotMember  = "team-maintainer-not-member"
+	RuleExcludedMemberNotInOrg   = "excluded-member-not-in-org"
+	RuleSecretChildTeam          = "secret-child-team"
+	RuleCodeOwnersUnknownRepo    = "codeowners-unknown-repository"
+	RuleInvalidTeamSource        = "invalid-team-source"
+	RuleRepositoryUnknownTeam    = "repository-unknown-team"
+	RuleGlobalExcludedNotInOrg   = "global-excluded-member-not-in-org"
+	RuleInvalidOrgRole           = "invalid-org-role"
+	RuleProtectedMemberNotInOrg  = "protected-member-not-in-org"
+	RuleProtectedTeamNotFound    = "protected-team-not-found"
+	RuleInvalidProfile           = "invalid-profile"
+	RuleCollaboratorNoReason     = "collaborator-without-reason"
+	RuleCollaboratorNoSponsor    = "collaborator-without-sponsor"
+	RuleCollaboratorSponsorNotIn = "collaborator-sponsor-not-in-org"
+	RulePersonMultipleAccounts   = "person-multiple-accounts"
+	RulePersonDuplicateLogin     = "person-duplicate-login"
+	RulePersonUnknownManager     = "person-unknown-manager"
+)
+
+// Finding is a problem found in the configuration.
+type Finding struct {
+	// Rule is the stable ID of the check that failed.
+	Rule string `json:"rule"`
+
+	Message string `json:"message"`
+
+	// Path is the list of keys leading to the problematic value in the
+	// configuration file. Items of lists are identified by their value, or
+	// by their login for lists of mappings.
+	Path []string `json:"-"`
+
+	// File, Line and Column locate the problem in the configuration file.
+	// Line and Column are 0 if the location is unknown.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+func (f Finding) String() string {
+	switch {
+	case f.Line != 0:
+		return fmt.Sprintf("%s:%d:%d: %s (%s)", f.File, f.Line, f.Column, f.Message, f.Rule)
+	case f.File != "":
+		return fmt.Sprintf("%s: %s (%s)", f.File, f.Message, f.Rule)
+	default:
+		return fmt.Sprintf("%s (%s)", f.Message, f.Rule)
+	}
+}
+
+// Validate checks the referential integrity of the configuration and returns
+// all problems found, sorted by path.
+func Validate(cfg *Config) []Finding {
+	var findings []Finding
+	add := func(rule string, path []string, format string, a ...interface{}) {
+		findings = append(findings, Finding{
+			Rule:    rule,
+			Message: fmt.Sprintf(format, a...),
+			Path:    path,
+		})
+	}
+
+	for teamName, team := range cfg.AllTeams {
+		path := teamPath(cfg, teamName)
+		for _, member := range team.Members {
+			if _, ok := cfg.Members[member]; !ok {
+				add(RuleTeamMemberNotInOrg, appendPath(path, "members", member),
+					"member %q from team %q does not belong to organization", member, teamName)
+			}
+		}
+		for _, mentor := range team.Mentors {
+			if _, ok := cfg.Members[mentor]; !ok {
+				add(RuleTeamMentorNotInOrg, appendPath(path, "mentors", mentor),
+					"mentor %q from team %q does not belong to organization", mentor, teamName)
+			}
+		}
+		for _, maintainer := range team.Maintainers {
+			if !slices.Contains(team.Members, maintainer) {
+				add(RuleTeamMaintainerNotMember, appendPath(path, "maintainers", maintainer),
+					"maintainer %q from team %q is not a member of the team", maintainer, teamName)
+			}
+		}
+		for _, xMember := range team.CodeReviewAssignment.ExcludedMembers {
+			if _, ok := cfg.Members[xMember.Login]; !ok {
+				add(RuleExcludedMemberNotInOrg, appendPath(path, "codeReviewAssignment", "excludedMembers", xMember.Login),
+					"member %q from code review assignment of team %q does not belong to organization", xMember.Login, teamName)
+			}
+		}
+		for repoName := range team.CodeOwners {
+			if _, ok := cfg.Repositories[repoName]; !ok {
+				add(RuleCodeOwnersUnknownRepo, appendPath(path, "codeOwners", string(repoName)),
+					"code owners of team %q reference unknown repository %q", teamName, repoName)
+			}
+		}
+
+		if team.Source != nil {
+			if err := team.Source.check(); err != nil {
+				add(RuleInvalidTeamSource, appendPath(path, "source"),
+					"error in source of team %q: %s", teamName, err)
+			}
+		}
+
+		if team.ParentTeam != "" && githubv4.TeamPrivacy(team.Privacy) == githubv4.TeamPrivacySecret {
+			add(RuleSecretChildTeam, appendPath(path, "privacy"),
+				"error in team %q: child teams can't be secret", teamName)
+		}
+	}
+	for repoName, repo := range cfg.Repositories {
+		for permission, usersOrTeams := range repo {
+			if permission.IsUser() {
+				continue
+			}
+			for _, teamName := range usersOrTeams {
+				if _, ok := cfg.AllTeams[string(teamName)]; !ok {
+					add(RuleRepositoryUnknownTeam, []string{"repositories", string(repoName), string(permission), string(teamName)},
+						"team %q with %s permission on repository %q does not exist", teamName, permission, repoName)
+				}
+			}
+		}
+	}
+	for _, xMember := range cfg.ExcludeCRAFromAllTeams {
+		if _, ok := cfg.Members[xMember]; !ok {
+			add(RuleGlobalExcludedNotInOrg, []string{"excludeCodeReviewAssignmentFromAllTeams", xMember},
+				"member %q from globally excluded reviews, does not belong to the organization", xMember)
+		}
+	}
+	for login, member := range cfg.Members {
+		switch member.Role {
+		case "", OrgRoleAdmin, OrgRoleMember:
+		default:
+			add(RuleInvalidOrgRole, []string{"members", login, "role"},
+				"member %q has an invalid role %q, valid roles are %q and %q", login, member.Role, OrgRoleAdmin, OrgRoleMember)
+		}
+	}
+	for _, member := range cfg.Protected.Members {
+		if _, ok := cfg.Members[member]; !ok {
+			add(RuleProtectedMemberNotInOrg, []string{"protected", "members", member},
+				"protected member %q does not belong to the organization", member)
+		}
+	}
+	for _, teamName := range cfg.Protected.Teams {
+		if _, ok := cfg.AllTeams[teamName]; !ok {
+			add(RuleProtectedTeamNotFound, []string{"protected", "teams", teamName},
+				"protected team %q does not exist", teamName)
+		}
+	}
+	for profileName, profile := range cfg.Profiles {
+		if err := profile.check(cfg); err != nil {
+			add(RuleInvalidProfile, []string{"profiles", profileName},
+				"error in profile %q: %s", profileName, err)
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// ValidatePeople checks that no GitHub login belongs to more than one person,
+// that managers exist and that no person has more than one account in the
+// organization.
+func ValidatePeople(cfg *Config) []Finding {
+	var findings []Finding
+	add := func(rule string, path []string, format string, a ...interface{}) {
+		findings = append(findings, Finding{
+			Rule:    rule,
+			Message: fmt.Sprintf(format, a...),
+			Path:    path,
+		})
+	}
+
+	people := cfg.AllPeople()
+	owners := map[string]string{}
+	for _, id := range people.IDs() {
+		person := people[id]
+		for _, login := range append(slices.Clone(person.Logins), person.Bots...) {
+			if other, ok := owners[login]; ok && other != id {
+				add(RulePersonDuplicateLogin, []string{"people", id},
+					"login %q belongs to both people %q and %q", login, other, id)
+			}
+			owners[login] = id
+		}
+		if logins := cfg.MemberLogins(id); len(logins) > 1 {
+			add(RulePersonMultipleAccounts, []string{"people", id, "logins"},
+				"person %q has %d accounts in the organization: %s", id, len(logins), strings.Join(logins, ", "))
+		}
+		if _, ok := people[person.Manager]; person.Manager != "" && !ok {
+			add(RulePersonUnknownManager, []string{"people", id, "manager"},
+				"manager %q of person %q does not exist", person.Manager, id)
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// ValidateCollaborators checks that all outside collaborators have a reason
+// and a sponsor that belongs to the organization.
+func ValidateCollaborators(cfg *Config) []Finding {
+	var findings []Finding
+	for login, collaborator := range cfg.Collaborators {
+		path := []string{"outsideCollaborators", login}
+		if collaborator.Reason == "" {
+			findings = append(findings, Finding{
+				Rule:    RuleCollaboratorNoReason,
+				Message: fmt.Sprintf("outside collaborator %q does not have a reason", login),
+				Path:    path,
+			})
+		}
+		switch _, isMember := cfg.Members[collaborator.Sponsor]; {
+		case collaborator.Sponsor == "":
+			findings = append(findings, Finding{
+				Rule:    RuleCollaboratorNoSponsor,
+				Message: fmt.Sprintf("outside collaborator %q does not have a sponsor", login),
+				Path:    path,
+			})
+		case !isMember:
+			findings = append(findings, Finding{
+				Rule:    RuleCollaboratorSponsorNotIn,
+				Message: fmt.Sprintf("sponsor %q of outside collaborator %q does not belong to the organization", collaborator.Sponsor, login),
+				Path:    appendPath(path, "sponsor"),
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// teamPath returns the path of the given team in the configuration file,
+// nested in the children of its ancestors.
+func teamPath(cfg *Config, teamName string) []string {
+	path := []string{teamName}
+	for {
+		team, ok := cfg.AllTeams[teamName]
+		if !ok || team.ParentTeam == "" {
+			break
+		}
+		teamName = string(team.ParentTeam)
+		path = append([]string{teamName, "children"}, path...)
+	}
+	return append([]string{"teams"}, path...)
+}
+
+func appendPath(path []string, elems ...string) []string {
+	return append(slices.Clone(path), elems...)
+}
+
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		pi, pj := strings.Join(findings[i].Path, "\x00"), strings.Join(findings[j].Path, "\x00")
+		if pi != pj {
+			return pi < pj
+		}
+		return findings[i].Message < findings[j].Message
+	})
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		// change breaks the configuration before validating it.
+		change func(cfg *Config)
+		want   []Finding
+	}{
+		{
+			name:   "valid",
+			change: func(cfg *Config) {},
+		},
+		{
+			name: "team members",
+			change: func(cfg *Config) {
+				child := cfg.Teams["parent"].Children["child"]
+				child.Members = append(child.Members, "carol")
+				child.Mentors = []string{"dave"}
+				child.Maintainers = []string{"alice"}
+				child.CodeReviewAssignment.ExcludedMembers = []ExcludedMember{{Login: "erin"}}
+			},
+			want: []Finding{
+				{
+					Rule:    RuleExcludedMemberNotInOrg,
+					Message: `member "erin" from code review assignment of team "child" does not belong to organization`,
+					Path:    []string{"teams", "parent", "children", "child", "codeReviewAssignment", "excludedMembers", "erin"},
+				},
+				{
+					Rule:    RuleTeamMaintainerNotMember,
+					Message: `maintainer "alice" from team "child" is not a member of the team`,
+					Path:    []string{"teams", "parent", "children", "child", "maintainers", "alice"},
+				},
+				{
+					Rule:    RuleTeamMemberNotInOrg,
+					Message: `member "carol" from team "child" does not belong to organization`,
+					Path:    []string{"teams", "parent", "children", "child", "members", "carol"},
+				},
+				{
+					Rule:    RuleTeamMentorNotInOrg,
+					Message: `mentor "dave" from team "child" does not belong to organization`,
+					Path:    []string{"teams", "parent", "children", "child", "mentors", "dave"},
+				},
+			},
+		},
+		{
+			name: "team settings",
+			change: func(cfg *Config) {
+				cfg.Teams["parent"].Source = &GroupSource{Group: "maintainers"}
+				child := cfg.Teams["parent"].Children["child"]
+				child.Privacy = "SECRET"
+				child.CodeOwners = map[RepositoryName][]string{"docs": {"*"}}
+			},
+			want: []Finding{
+				{
+					Rule:    RuleCodeOwnersUnknownRepo,
+					Message: `code owners of team "child" reference unknown repository "docs"`,
+					Path:    []string{"teams", "parent", "children", "child", "codeOwners", "docs"},
+				},
+				{
+					Rule:    RuleSecretChildTeam,
+					Message: `error in team "child": child teams can't be secret`,
+					Path:    []string{"teams", "parent", "children", "child", "privacy"},
+				},
+				{
+					Rule:    RuleInvalidTeamSource,
+					Message: `error in source of team "parent": one of csv, ldif or ldap must be set`,
+					Path:    []string{"teams", "parent", "source"},
+				},
+			},
+		},
+		{
+			name: "organization",
+			change: func(cfg *Config) {
+				cfg.Repositories["cilium"]["READ"] = []TeamOrMemberName{"ghost"}
+				cfg.Repositories["cilium"]["USER-READ"] = []TeamOrMemberName{"eve"}
+				cfg.ExcludeCRAFromAllTeams = append(cfg.ExcludeCRAFromAllTeams, "carol")
+				cfg.Members["bob"] = User{ID: "U2", Role: "owner"}
+				cfg.Protected = Protected{Members: []string{"carol"}, Teams: []string{"ghost"}}
+				cfg.Profiles["committer"] = Profile{Teams: []string{"ghost"}}
+			},
+			want: []Finding{
+				{
+					Rule:    RuleGlobalExcludedNotInOrg,
+					Message: `member "carol" from globally excluded reviews, does not belong to the organization`,
+					Path:    []string{"excludeCodeReviewAssignmentFromAllTeams", "carol"},
+				},
+				{
+					Rule:    RuleInvalidOrgRole,
+					Message: `member "bob" has an invalid role "owner", valid roles are "admin" and "member"`,
+					Path:    []string{"members", "bob", "role"},
+				},
+				{
+					Rule:    RuleInvalidProfile,
+					Message: `error in profile "committer": team "ghost" does not exist`,
+					Path:    []string{"profiles", "committer"},
+				},
+				{
+					Rule:    RuleProtectedMemberNotInOrg,
+					Message: `protected member "carol" does not belong to the organization`,
+					Path:    []string{"protected", "members", "carol"},
+				},
+				{
+					Rule:    RuleProtectedTeamNotFound,
+					Message: `protected team "ghost" does not exist`,
+					Path:    []string{"protected", "teams", "ghost"},
+				},
+				{
+					Rule:    RuleRepositoryUnknownTeam,
+					Message: `team "ghost" with READ permission on repository "cilium" does not exist`,
+					Path:    []string{"repositories", "cilium", "READ", "ghost"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := renameConfig()
+			tt.change(cfg)
+			got := Validate(cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCollaborators(t *testing.T) {
+	tests := []struct {
+		name         string
+		collaborator OutsideCollaborator
+		want         []Finding
+	}{
+		{
+			name:         "valid",
+			collaborator: OutsideCollaborator{Reason: "contractor", Sponsor: "alice"},
+		},
+		{
+			name:         "no reason nor sponsor",
+			collaborator: OutsideCollaborator{},
+			want: []Finding{
+				{
+					Rule:    RuleCollaboratorNoReason,
+					Message: `outside collaborator "eve" does not have a reason`,
+					Path:    []string{"outsideCollaborators", "eve"},
+				},
+				{
+					Rule:    RuleCollaboratorNoSponsor,
+					Message: `outside collaborator "eve" does not have a sponsor`,
+					Path:    []string{"outsideCollaborators", "eve"},
+				},
+			},
+		},
+		{
+			name:         "sponsor not in the organization",
+			collaborator: OutsideCollaborator{Reason: "contractor", Sponsor: "carol"},
+			want: []Finding{
+				{
+					Rule:    RuleCollaboratorSponsorNotIn,
+					Message: `sponsor "carol" of outside collaborator "eve" does not belong to the organization`,
+					Path:    []string{"outsideCollaborators", "eve", "sponsor"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := renameConfig()
+			cfg.Collaborators["eve"] = tt.collaborator
+			got := ValidateCollaborators(cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateCollaborators() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	finding := Finding{Rule: RuleProtectedTeamNotFound, Message: `protected team "ghost" does not exist`}
+	tests := []struct {
+		file         string
+		line, column int
+		want         string
+	}{
+		{"", 0, 0, `protected team "ghost" does not exist (protected-team-not-found)`},
+		{"team-assignments.yaml", 0, 0, `team-assignments.yaml: protected team "ghost" does not exist (protected-team-not-found)`},
+		{"team-assignments.yaml", 12, 7, `team-assignments.yaml:12:7: protected team "ghost" does not exist (protected-team-not-found)`},
+	}
+	for _, tt := range tests {
+		f := finding
+		f.File, f.Line, f.Column = tt.file, tt.line, tt.column
+		if got := f.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
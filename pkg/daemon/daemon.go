@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package daemon periodically reconciles the local configuration with GitHub
+// and exposes the result of the last reconciliation over HTTP.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Mode is what the daemon does when GitHub drifts from the local
+// configuration.
+type Mode string
+
+const (
+	// ModeReport only reports the drift.
+	ModeReport Mode = "report"
+	// ModeEnforce pushes the local configuration into GitHub to revert the
+	// drift.
+	ModeEnforce Mode = "enforce"
+)
+
+// ParseMode returns the mode with the given name.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeReport, ModeEnforce:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown mode %q, must be %q or %q", s, ModeReport, ModeEnforce)
+	}
+}
+
+// ReconcileFunc compares the local configuration with GitHub and returns the
+// drift between them, or an empty string if there is none. With enforce, it
+// also pushes the local configuration into GitHub if they drifted.
+type ReconcileFunc func(ctx context.Context, enforce bool) (diff string, err error)
+
+// Status is the result of the last reconciliation.
+type Status struct {
+	Mode     Mode   `json:"mode"`
+	Interval string `json:"interval"`
+
+	// Runs is the number of reconciliations started.
+	Runs int `json:"runs"`
+
+	// Running is true while a reconciliation is in progress.
+	Running bool `json:"running"`
+
+	// LastRun and LastSuccess are the times when the last reconciliation
+	// finished, and the last one that didn't fail.
+	LastRun     time.Time `json:"lastRun,omitempty"`
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+
+	// LastError is the error of the last reconciliation, if it failed.
+	LastError string `json:"lastError,omitempty"`
+
+	// Drift is true if GitHub drifted from the local configuration in the
+	// last reconciliation, and Diff is that drift.
+	Drift bool   `json:"drift"`
+	Diff  string `json:"diff,omitempty"`
+
+	// Enforced is true if the drift of the last reconciliation was reverted.
+	Enforced bool `json:"enforced"`
+
+	// Events are the last events checked, most recent first.
+	Events []EventStatus `json:"events,omitempty"`
+}
+
+// Daemon runs a reconciliation periodically.
+type Daemon struct {
+	reconcile ReconcileFunc
+	interval  time.Duration
+	events    chan event
+	trigger   chan struct{}
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New returns a daemon that runs reconcile every interval in the given mode.
+func New(reconcile ReconcileFunc, mode Mode, interval time.Duration) *Daemon {
+	return &Daemon{
+		reconcile: reconcile,
+		interval:  interval,
+		events:    make(chan event, maxPendingEvents),
+		trigger:   make(chan struct{}, 1),
+		status: Status{
+			Mode:     mode,
+			Interval: interval.String(),
+		},
+	}
+}
+
+// Status returns the status of the daemon.
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := d.status
+	status.Events = slices.Clone(d.status.Events)
+	return status
+}
+
+// Run runs a reconciliation immediately and then every interval until ctx is
+// done, checking the queued events in between. A reconciliation or check in
+// progress when ctx is done is not interrupted, Run returns once it finishes.
+func (d *Daemon) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	runCtx := context.WithoutCancel(ctx)
+	run := true
+	for {
+		if run {
+			d.runOnce(runCtx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run = true
+		case <-d.trigger:
+			run = true
+		case e := <-d.events:
+			run = d.checkEvent(runCtx, e)
+		}
+	}
+}
+
+// Trigger requests a reconciliation as soon as the one in progress, if any,
+// finishes.
+func (d *Daemon) Trigger() {
+	select {
+	case d.trigger <- struct{}{}:
+	default:
+		// A reconciliation is already pending.
+	}
+}
+
+func (d *Daemon) runOnce(ctx context.Context) {
+	d.mu.Lock()
+	d.status.Runs++
+	d.status.Running = true
+	enforce := d.status.Mode == ModeEnforce
+	d.mu.Unlock()
+
+	diff, err := d.reconcile(ctx, enforce)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Running = false
+	d.status.LastRun = time.Now()
+	// A failed enforcement still reports the drift it tried to revert.
+	if err == nil || diff != "" {
+		d.status.Drift = diff != ""
+		d.status.Diff = diff
+	}
+	d.status.Enforced = err == nil && enforce && diff != ""
+	if err != nil {
+		d.status.LastError = err.Error()
+		fmt.Printf("[ERROR] reconciliation failed: %s\n", err)
+		return
+	}
+	d.status.LastError = ""
+	d.status.LastSuccess = d.status.LastRun
+	switch {
+	case d.status.Enforced:
+		fmt.Printf("GitHub drifted from the local configuration, pushed the local configuration:\n%s", diff)
+	case d.status.Drift:
+		fmt.Printf("GitHub drifted from the local configuration:\n%s", diff)
+	default:
+		fmt.Printf("GitHub is in sync with the local configuration\n")
+	}
+}
+
+// Handler returns the HTTP handler of the daemon, which serves:
+//
+//   - /healthz: 200 unless the last reconciliation failed.
+//   - /status: the status of the daemon as JSON.
+//   - /diff: the drift of the last reconciliation as plain text.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		if status := d.Status(); status.LastError != "" {
+			http.Error(w, status.LastError, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "ok\n")
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(d.Status())
+	})
+	mux.HandleFunc("GET /diff", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, d.Status().Diff)
+	})
+	return mux
+}
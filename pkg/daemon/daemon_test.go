@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeReconciler is a ReconcileFunc that returns diff and err, and reports
+// every call on calls.
+type fakeReconciler struct {
+	calls chan bool
+
+	mu   sync.Mutex
+	diff string
+	err  error
+}
+
+func newFakeReconciler(diff string, err error) *fakeReconciler {
+	return &fakeReconciler{calls: make(chan bool, 100), diff: diff, err: err}
+}
+
+func (f *fakeReconciler) reconcile(_ context.Context, enforce bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls <- enforce
+	return f.diff, f.err
+}
+
+// waitCalls waits for n calls of the reconciler and returns the enforce
+// argument of each.
+func (f *fakeReconciler) waitCalls(t *testing.T, n int) []bool {
+	t.Helper()
+	var got []bool
+	for range n {
+		select {
+		case enforce := <-f.calls:
+			got = append(got, enforce)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d reconciliations, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+// noMoreCalls fails if the reconciler is called again shortly.
+func (f *fakeReconciler) noMoreCalls(t *testing.T) {
+	t.Helper()
+	select {
+	case <-f.calls:
+		t.Fatalf("unexpected reconciliation")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// runDaemon runs d in the background until the test ends.
+func runDaemon(t *testing.T, d *Daemon) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// waitFor waits until cond is true.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunTicker(t *testing.T) {
+	for _, mode := range []Mode{ModeReport, ModeEnforce} {
+		t.Run(string(mode), func(t *testing.T) {
+			f := newFakeReconciler("", nil)
+			d := New(f.reconcile, mode, 10*time.Millisecond)
+			runDaemon(t, d)
+
+			for i, enforce := range f.waitCalls(t, 3) {
+				if enforce != (mode == ModeEnforce) {
+					t.Errorf("reconciliation %d: enforce = %v in mode %q", i, enforce, mode)
+				}
+			}
+		})
+	}
+}
+
+func TestRunStops(t *testing.T) {
+	f := newFakeReconciler("", nil)
+	d := New(f.reconcile, ModeReport, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	f.waitCalls(t, 1)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run() didn't return once its context was done")
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	f := newFakeReconciler("", nil)
+	d := New(f.reconcile, ModeReport, time.Hour)
+
+	// Triggers requested while a reconciliation is pending are merged.
+	d.Trigger()
+	d.Trigger()
+	runDaemon(t, d)
+	f.waitCalls(t, 2)
+	f.noMoreCalls(t)
+
+	d.Trigger()
+	f.waitCalls(t, 1)
+	f.noMoreCalls(t)
+	if got := d.Status().Runs; got != 3 {
+		t.Errorf("Status().Runs = %d, want 3", got)
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	errCheck := errors.New("check failed")
+	tests := []struct {
+		name  string
+		mode  Mode
+		drift []string
+		err   error
+		want  EventStatus
+		// reconcile is true if the event triggers a reconciliation.
+		reconcile bool
+	}{
+		{
+			name: "no drift",
+			mode: ModeEnforce,
+			want: EventStatus{Event: "team foo"},
+		},
+		{
+			name:  "drift reported",
+			mode:  ModeReport,
+			drift: []string{"add member bar"},
+			want:  EventStatus{Event: "team foo", Drift: []string{"add member bar"}},
+		},
+		{
+			name:      "drift enforced",
+			mode:      ModeEnforce,
+			drift:     []string{"add member bar"},
+			want:      EventStatus{Event: "team foo", Drift: []string{"add member bar"}, Enforced: true},
+			reconcile: true,
+		},
+		{
+			name: "check failed",
+			mode: ModeEnforce,
+			err:  errCheck,
+			want: EventStatus{Event: "team foo", Error: errCheck.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeReconciler("", nil)
+			d := New(f.reconcile, tt.mode, time.Hour)
+			runDaemon(t, d)
+			f.waitCalls(t, 1)
+
+			d.Enqueue("team foo", func(context.Context) ([]string, error) {
+				return tt.drift, tt.err
+			})
+			waitFor(t, func() bool { return len(d.Status().Events) == 1 })
+
+			if tt.reconcile {
+				f.waitCalls(t, 1)
+			}
+			f.noMoreCalls(t)
+
+			got := d.Status().Events[0]
+			if got.Received.IsZero() || got.Checked.Before(got.Received) {
+				t.Errorf("event received at %v and checked at %v", got.Received, got.Checked)
+			}
+			got.Received, got.Checked = time.Time{}, time.Time{}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Status().Events[0] = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueTooManyEvents(t *testing.T) {
+	f := newFakeReconciler("", nil)
+	d := New(f.reconcile, ModeReport, time.Hour)
+	check := func(context.Context) ([]string, error) { return nil, nil }
+
+	for i := range maxPendingEvents {
+		d.Enqueue(fmt.Sprintf("event %d", i), check)
+	}
+	if len(d.trigger) != 0 {
+		t.Fatalf("reconciliation triggered with %d pending events", maxPendingEvents)
+	}
+	d.Enqueue("dropped", check)
+	if len(d.events) != maxPendingEvents {
+		t.Errorf("%d pending events, want %d", len(d.events), maxPendingEvents)
+	}
+	if len(d.trigger) != 1 {
+		t.Errorf("reconciliation not triggered when dropping an event")
+	}
+}
+
+func TestEventStatusesLimit(t *testing.T) {
+	f := newFakeReconciler("", nil)
+	d := New(f.reconcile, ModeReport, time.Hour)
+	for i := range maxEventStatuses + 5 {
+		d.checkEvent(context.Background(), event{
+			name:  fmt.Sprintf("event %d", i),
+			check: func(context.Context) ([]string, error) { return nil, nil },
+		})
+	}
+
+	events := d.Status().Events
+	if len(events) != maxEventStatuses {
+		t.Fatalf("%d events in status, want %d", len(events), maxEventStatuses)
+	}
+	if want := fmt.Sprintf("event %d", maxEventStatuses+4); events[0].Event != want {
+		t.Errorf("most recent event is %q, want %q", events[0].Event, want)
+	}
+}
+
+func TestRunOnceStatus(t *testing.T) {
+	errReconcile := errors.New("reconciliation failed")
+	tests := []struct {
+		name string
+		mode Mode
+		// previous is the result of the previous reconciliation, if any.
+		previous *fakeReconciler
+		diff     string
+		err      error
+
+		wantDrift     bool
+		wantDiff      string
+		wantEnforced  bool
+		wantLastError string
+		wantSuccess   bool
+	}{
+		{
+			name:        "in sync",
+			mode:        ModeEnforce,
+			wantSuccess: true,
+		},
+		{
+			name:        "drift reported",
+			mode:        ModeReport,
+			diff:        "+ foo\n",
+			wantDrift:   true,
+			wantDiff:    "+ foo\n",
+			wantSuccess: true,
+		},
+		{
+			name:         "drift enforced",
+			mode:         ModeEnforce,
+			diff:         "+ foo\n",
+			wantDrift:    true,
+			wantDiff:     "+ foo\n",
+			wantEnforced: true,
+			wantSuccess:  true,
+		},
+		{
+			name:          "failed enforcement reports its drift",
+			mode:          ModeEnforce,
+			diff:          "+ foo\n",
+			err:           errReconcile,
+			wantDrift:     true,
+			wantDiff:      "+ foo\n",
+			wantLastError: errReconcile.Error(),
+		},
+		{
+			name:          "failed comparison keeps the previous drift",
+			mode:          ModeReport,
+			previous:      newFakeReconciler("+ bar\n", nil),
+			err:           errReconcile,
+			wantDrift:     true,
+			wantDiff:      "+ bar\n",
+			wantLastError: errReconcile.Error(),
+			wantSuccess:   true,
+		},
+		{
+			name:        "success clears the previous error",
+			mode:        ModeReport,
+			previous:    newFakeReconciler("", errReconcile),
+			wantSuccess: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeReconciler(tt.diff, tt.err)
+			d := New(f.reconcile, tt.mode, time.Hour)
+			if tt.previous != nil {
+				d.reconcile = tt.previous.reconcile
+				d.runOnce(context.Background())
+				d.reconcile = f.reconcile
+			}
+			d.runOnce(context.Background())
+
+			got := d.Status()
+			if got.Running {
+				t.Errorf("Status().Running = true after the reconciliation")
+			}
+			if got.LastRun.IsZero() {
+				t.Errorf("Status().LastRun not set")
+			}
+			if got.Drift != tt.wantDrift || got.Diff != tt.wantDiff {
+				t.Errorf("Status() drift = %v %q, want %v %q", got.Drift, got.Diff, tt.wantDrift, tt.wantDiff)
+			}
+			if got.Enforced != tt.wantEnforced {
+				t.Errorf("Status().Enforced = %v, want %v", got.Enforced, tt.wantEnforced)
+			}
+			if got.LastError != tt.wantLastError {
+				t.Errorf("Status().LastError = %q, want %q", got.LastError, tt.wantLastError)
+			}
+			if !got.LastSuccess.IsZero() != tt.wantSuccess {
+				t.Errorf("Status().LastSuccess = %v, want it set: %v", got.LastSuccess, tt.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	f := newFakeReconciler("+ foo\n", nil)
+	d := New(f.reconcile, ModeReport, time.Minute)
+	d.runOnce(context.Background())
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %s", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("GET %s: %s", path, err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/healthz"); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("GET /healthz = %d %q, want %d %q", code, body, http.StatusOK, "ok\n")
+	}
+	if code, body := get("/diff"); code != http.StatusOK || body != "+ foo\n" {
+		t.Errorf("GET /diff = %d %q, want %d %q", code, body, http.StatusOK, "+ foo\n")
+	}
+	code, body := get("/status")
+	if code != http.StatusOK {
+		t.Fatalf("GET /status = %d, want %d", code, http.StatusOK)
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(body), &status); err != nil {
+		t.Fatalf("GET /status returned invalid JSON: %s", err)
+	}
+	if status.Mode != ModeReport || status.Interval != "1m0s" || status.Runs != 1 || !status.Drift {
+		t.Errorf("GET /status = %+v", status)
+	}
+
+	f.err = errors.New("reconciliation failed")
+	d.runOnce(context.Background())
+	if code, body := get("/healthz"); code != http.StatusServiceUnavailable || body != "reconciliation failed\n" {
+		t.Errorf("GET /healthz = %d %q, want %d %q", code, body, http.StatusServiceUnavailable, "reconciliation failed\n")
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPendingEvents is the number of events that can be queued before
+	// falling back to a full reconciliation.
+	maxPendingEvents = 100
+
+	// maxEventStatuses is the number of events kept in the status.
+	maxEventStatuses = 20
+)
+
+// CheckFunc compares a single entity of the local configuration with GitHub
+// and returns how it drifted, one change per line.
+type CheckFunc func(ctx context.Context) (drift []string, err error)
+
+// EventStatus is the result of the check of an event.
+type EventStatus struct {
+	// Event describes the event and the entity it affected.
+	Event string `json:"event"`
+
+	Received time.Time `json:"received"`
+	Checked  time.Time `json:"checked"`
+
+	Drift []string `json:"drift,omitempty"`
+	Error string   `json:"error,omitempty"`
+
+	// Enforced is true if the drift triggered a reconciliation to revert it.
+	Enforced bool `json:"enforced"`
+}
+
+type event struct {
+	name     string
+	received time.Time
+	check    CheckFunc
+}
+
+// Enqueue queues the check of an event between reconciliations. In enforce
+// mode, a drift triggers a reconciliation to revert it. If too many events are
+// pending, the event is dropped and a full reconciliation is triggered
+// instead.
+func (d *Daemon) Enqueue(name string, check CheckFunc) {
+	select {
+	case d.events <- event{name: name, received: time.Now(), check: check}:
+	default:
+		fmt.Printf("[WARN] too many pending events, dropping %s and triggering a reconciliation\n", name)
+		d.Trigger()
+	}
+}
+
+// checkEvent checks the event and returns true if a reconciliation must run
+// to revert its drift.
+func (d *Daemon) checkEvent(ctx context.Context, e event) bool {
+	drift, err := e.check(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := EventStatus{
+		Event:    e.name,
+		Received: e.received,
+		Checked:  time.Now(),
+		Drift:    drift,
+		Enforced: err == nil && len(drift) != 0 && d.status.Mode == ModeEnforce,
+	}
+	switch {
+	case err != nil:
+		status.Error = err.Error()
+		fmt.Printf("[ERROR] failed to check %s: %s\n", e.name, err)
+	case len(drift) == 0:
+		fmt.Printf("%s: no drift\n", e.name)
+	default:
+		fmt.Printf("%s: GitHub drifted from the local configuration:\n  %s\n", e.name, strings.Join(drift, "\n  "))
+	}
+	d.status.Events = append([]EventStatus{status}, d.status.Events...)
+	if len(d.status.Events) > maxEventStatuses {
+		d.status.Events = d.status.Events[:maxEventStatuses]
+	}
+	return status.Enforced
+}
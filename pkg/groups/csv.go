@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package groups
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// csvMembers returns the identities of the rows of the CSV file whose 'group'
+// column is group, and the locations of the rows without any identifier.
+func csvMembers(file, group string) ([]config.Identity, []string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("%s is empty", file)
+	}
+
+	column := func(name string) int {
+		return slices.IndexFunc(rows[0], func(header string) bool {
+			return strings.EqualFold(strings.TrimSpace(header), name)
+		})
+	}
+	groupCol := column("group")
+	if groupCol == -1 {
+		return nil, nil, fmt.Errorf("%s doesn't have a 'group' column", file)
+	}
+	emailCol, employeeIDCol, loginCol := column("email"), column("employeeID"), column("login")
+	if emailCol == -1 && employeeIDCol == -1 && loginCol == -1 {
+		return nil, nil, fmt.Errorf("%s doesn't have any of the 'email', 'employeeID' or 'login' columns", file)
+	}
+
+	value := func(row []string, col int) string {
+		if col == -1 {
+			return ""
+		}
+		return strings.TrimSpace(row[col])
+	}
+
+	var identities []config.Identity
+	var unresolvable []string
+	for i, row := range rows[1:] {
+		if value(row, groupCol) != group {
+			continue
+		}
+		id := config.Identity{
+			Email:      value(row, emailCol),
+			EmployeeID: value(row, employeeIDCol),
+			Login:      value(row, loginCol),
+		}
+		if id == (config.Identity{}) {
+			unresolvable = append(unresolvable, fmt.Sprintf("%s:%d", file, i+2))
+			continue
+		}
+		identities = append(identities, id)
+	}
+	return identities, unresolvable, nil
+}
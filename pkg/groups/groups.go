@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package groups reads the members of the external groups that are the source
+// of the members of teams, such as CSV files, LDIF exports or LDAP servers.
+package groups
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Members returns the identities of the members of the group of the given
+// source, along with the members that can't be identified, such as DNs
+// without an entry or rows without any identifier. Relative file paths are
+// relative to dir.
+func Members(ctx context.Context, source *config.GroupSource, dir string) ([]config.Identity, []string, error) {
+	switch {
+	case source.CSV != "":
+		return csvMembers(path(dir, source.CSV), source.Group)
+	case source.LDIF != "":
+		d, err := readLDIF(path(dir, source.LDIF))
+		if err != nil {
+			return nil, nil, err
+		}
+		return groupMembers(ctx, d, source.Group)
+	case source.LDAP != "":
+		d, err := dialLDAP(ctx, source.LDAP)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer d.close()
+		return groupMembers(ctx, d, source.Group)
+	default:
+		return nil, nil, fmt.Errorf("unknown source %s", source)
+	}
+}
+
+func path(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// entry is an entry of a directory, mapping the lower case name of each
+// attribute to its values.
+type entry map[string][]string
+
+func (e entry) first(attrs ...string) string {
+	for _, attr := range attrs {
+		if values := e[strings.ToLower(attr)]; len(values) != 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// directory is a directory with entries identified by their DN.
+type directory interface {
+	// entry returns the entry with the given DN, or nil if it doesn't exist.
+	entry(ctx context.Context, dn string) (entry, error)
+}
+
+// groupMembers returns the identities of the members of the group entry with
+// the given DN, and the DNs of the members that can't be identified.
+func groupMembers(ctx context.Context, d directory, groupDN string) ([]config.Identity, []string, error) {
+	group, err := d.entry(ctx, groupDN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read group %q: %w", groupDN, err)
+	}
+	if group == nil {
+		return nil, nil, fmt.Errorf("group %q not found", groupDN)
+	}
+
+	var identities []config.Identity
+	var unresolvable []string
+	for _, attr := range []string{"member", "uniquemember"} {
+		for _, dn := range group[attr] {
+			member, err := d.entry(ctx, dn)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read member %q of group %q: %w", dn, groupDN, err)
+			}
+			var id config.Identity
+			if member != nil {
+				id = identity(member)
+			} else {
+				// Fall back to the user ID in the DN of members without an
+				// entry, e.g. "uid=jdoe,ou=people,dc=example,dc=com".
+				id = config.Identity{ID: rdnValue(dn, "uid")}
+			}
+			if id == (config.Identity{}) {
+				unresolvable = append(unresolvable, dn)
+				continue
+			}
+			identities = append(identities, id)
+		}
+	}
+	for _, uid := range group["memberuid"] {
+		if uid = strings.TrimSpace(uid); uid == "" {
+			continue
+		}
+		identities = append(identities, config.Identity{ID: uid})
+	}
+	return identities, unresolvable, nil
+}
+
+func identity(e entry) config.Identity {
+	return config.Identity{
+		ID:         e.first("uid"),
+		Email:      e.first("mail"),
+		EmployeeID: e.first("employeeNumber", "employeeID"),
+		Login:      e.first("githubLogin"),
+	}
+}
+
+// rdnValue returns the value of the first RDN of dn if its attribute is attr.
+func rdnValue(dn, attr string) string {
+	rdn, _, _ := strings.Cut(dn, ",")
+	name, value, ok := strings.Cut(rdn, "=")
+	if !ok || !strings.EqualFold(strings.TrimSpace(name), attr) {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// normalizeDN returns dn in a form suitable to compare it with other DNs.
+func normalizeDN(dn string) string {
+	rdns := strings.Split(dn, ",")
+	for i, rdn := range rdns {
+		name, value, _ := strings.Cut(rdn, "=")
+		rdns[i] = strings.ToLower(strings.TrimSpace(name)) + "=" + strings.ToLower(strings.TrimSpace(value))
+	}
+	return strings.Join(rdns, ",")
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package groups
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestCSVMembers(t *testing.T) {
+	tests := []struct {
+		name             string
+		file             string
+		group            string
+		want             []config.Identity
+		wantUnresolvable []string
+		wantErr          string
+	}{
+		{
+			name:  "rows of the group",
+			file:  "groups.csv",
+			group: "sig-datapath",
+			want: []config.Identity{
+				{Email: "alice@example.com", EmployeeID: "1001"},
+				{Email: "bob@example.com", Login: "bob-gh"},
+			},
+			wantUnresolvable: []string{filepath.Join("testdata", "groups.csv") + ":5"},
+		},
+		{
+			name:  "other group",
+			file:  "groups.csv",
+			group: "sig-policy",
+			want: []config.Identity{
+				{Email: "carol@example.com", EmployeeID: "1003", Login: "carol"},
+			},
+		},
+		{
+			name:  "unknown group",
+			file:  "groups.csv",
+			group: "sig-unknown",
+		},
+		{
+			name:    "missing group column",
+			file:    "no-group.csv",
+			group:   "sig-datapath",
+			wantErr: "doesn't have a 'group' column",
+		},
+		{
+			name:    "missing identifier columns",
+			file:    "no-identifier.csv",
+			group:   "sig-datapath",
+			wantErr: "doesn't have any of the 'email', 'employeeID' or 'login' columns",
+		},
+		{
+			name:    "missing file",
+			file:    "missing.csv",
+			group:   "sig-datapath",
+			wantErr: "no such file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unresolvable, err := csvMembers(filepath.Join("testdata", tt.file), tt.group)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("csvMembers() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("csvMembers() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("csvMembers() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(unresolvable, tt.wantUnresolvable) {
+				t.Errorf("csvMembers() unresolvable = %v, want %v", unresolvable, tt.wantUnresolvable)
+			}
+		})
+	}
+}
+
+func TestReadLDIF(t *testing.T) {
+	d, err := readLDIF(filepath.Join("testdata", "directory.ldif"))
+	if err != nil {
+		t.Fatalf("readLDIF() error = %v", err)
+	}
+	if len(d) != 4 {
+		t.Errorf("readLDIF() returned %d entries, want 4", len(d))
+	}
+
+	tests := []struct {
+		dn   string
+		attr string
+		want []string
+	}{
+		{"cn=sig-datapath,ou=groups,dc=example,dc=com", "memberuid", []string{"erin"}},
+		{"CN=sig-datapath, OU=groups, DC=example, DC=com", "cn", []string{"sig-datapath"}},
+		// Folded lines are unfolded.
+		{"uid=bob,ou=people,dc=example,dc=com", "mail", []string{"bob@example.com"}},
+		// Base64 values and DNs are decoded.
+		{"uid=zoe,ou=people,dc=example,dc=com", "cn", []string{"Zoë Example"}},
+	}
+	for _, tt := range tests {
+		e, err := d.entry(context.Background(), tt.dn)
+		if err != nil {
+			t.Fatalf("entry(%q) error = %v", tt.dn, err)
+		}
+		if got := e[tt.attr]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("entry(%q)[%q] = %q, want %q", tt.dn, tt.attr, got, tt.want)
+		}
+	}
+
+	if e, _ := d.entry(context.Background(), "uid=dave,ou=people,dc=example,dc=com"); e != nil {
+		t.Errorf("entry() of a missing DN = %v, want nil", e)
+	}
+}
+
+func TestReadLDIFErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"invalid line", "dn: uid=alice\ninvalid\n", ":2: invalid line"},
+		{"attribute outside of an entry", "uid: alice\n", ":1: attribute \"uid\" outside of an entry"},
+		{"change record", "dn: uid=alice\nchangetype: delete\n", ":2: change records are not supported"},
+		{"URL value", "dn: uid=alice\njpegPhoto:< file:///photo.jpg\n", ":2: values referenced by URLs are not supported"},
+		{"invalid base64", "dn: uid=alice\ncn:: !!!\n", ":2: invalid base64 value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readLDIF(writeFile(t, "directory.ldif", tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readLDIF() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	source := &config.GroupSource{LDIF: "directory.ldif", Group: "cn=sig-datapath,ou=groups,dc=example,dc=com"}
+	got, unresolvable, err := Members(context.Background(), source, "testdata")
+	if err != nil {
+		t.Fatalf("Members() error = %v", err)
+	}
+	want := []config.Identity{
+		{ID: "alice", Email: "alice@example.com", EmployeeID: "1001"},
+		{ID: "bob", Email: "bob@example.com", Login: "bob-gh"},
+		// The user ID of members without an entry is taken from their DN.
+		{ID: "dave"},
+		{ID: "erin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Members() = %+v, want %+v", got, want)
+	}
+	wantUnresolvable := []string{"cn=contractors,ou=groups,dc=example,dc=com"}
+	if !reflect.DeepEqual(unresolvable, wantUnresolvable) {
+		t.Errorf("Members() unresolvable = %v, want %v", unresolvable, wantUnresolvable)
+	}
+
+	source.Group = "cn=sig-unknown,ou=groups,dc=example,dc=com"
+	if _, _, err := Members(context.Background(), source, "testdata"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Members() of a missing group error = %v, want not found", err)
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
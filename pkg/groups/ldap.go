@@ -0,0 +1,379 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package groups
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// LDAP result codes used by the client.
+const (
+	ldapSuccess      = 0
+	ldapNoSuchObject = 32
+)
+
+const (
+	// ldapTimeout is the timeout of the connection and of each request.
+	ldapTimeout = 30 * time.Second
+
+	// maxMessageSize is the maximum size of the messages read from the
+	// server.
+	maxMessageSize = 16 << 20
+)
+
+// ldap is a minimal LDAPv3 client that only supports simple binds and reading
+// entries by their DN.
+type ldap struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	nextID int
+}
+
+// dialLDAP connects to the LDAP server of the given ldap:// or ldaps:// URL,
+// binding with the credentials of the LDAP_BIND_DN and LDAP_BIND_PASSWORD
+// environment variables, or anonymously if they are not set.
+func dialLDAP(ctx context.Context, rawURL string) (*ldap, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP URL %q: %w", rawURL, err)
+	}
+
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.DialContext(ctx, "tcp", hostPort(u.Host, "389"))
+	case "ldaps":
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", hostPort(u.Host, "636"))
+	default:
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", u.Host, err)
+	}
+
+	return newLDAP(ctx, conn, os.Getenv("LDAP_BIND_DN"), os.Getenv("LDAP_BIND_PASSWORD"))
+}
+
+// newLDAP returns a client using conn, bound with the given credentials, or
+// anonymously if dn is empty. conn is closed if the bind fails.
+func newLDAP(ctx context.Context, conn net.Conn, dn, password string) (*ldap, error) {
+	l := &ldap{conn: conn, r: bufio.NewReader(conn), nextID: 1}
+	if err := l.bind(ctx, dn, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// deadline sets the deadline of the request, which expires after ldapTimeout,
+// when ctx expires or when ctx is canceled, whatever happens first. The
+// returned function must be called once the request is done.
+func (l *ldap) deadline(ctx context.Context) (stop func() bool) {
+	deadline := time.Now().Add(ldapTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	l.conn.SetDeadline(deadline)
+	return context.AfterFunc(ctx, func() {
+		// Unblock the reads and writes in progress.
+		l.conn.SetDeadline(time.Unix(1, 0))
+	})
+}
+
+// ctxErr returns the error of ctx if it's done, since the error of the
+// connection is only a consequence of it, or err otherwise.
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func hostPort(host, defaultPort string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, defaultPort)
+}
+
+func (l *ldap) close() error {
+	l.conn.SetDeadline(time.Now().Add(ldapTimeout))
+	// UnbindRequest ::= [APPLICATION 2] NULL
+	l.send(berTLV(0x42, nil))
+	return l.conn.Close()
+}
+
+func (l *ldap) bind(ctx context.Context, dn, password string) error {
+	defer l.deadline(ctx)()
+
+	// BindRequest ::= [APPLICATION 0] SEQUENCE {
+	//     version INTEGER, name LDAPDN, authentication simple [0] OCTET STRING }
+	id, err := l.send(berTLV(0x60, berInt(0x02, 3), berTLV(0x04, []byte(dn)), berTLV(0x80, []byte(password))))
+	if err != nil {
+		return ctxErr(ctx, err)
+	}
+	resp, err := l.receive(id)
+	if err != nil {
+		return ctxErr(ctx, err)
+	}
+	if resp.tag != 0x61 {
+		return fmt.Errorf("unexpected LDAP response 0x%x to bind request", resp.tag)
+	}
+	if code, msg, err := ldapResult(resp); err != nil {
+		return err
+	} else if code != ldapSuccess {
+		return fmt.Errorf("failed to bind to LDAP server: result code %d: %s", code, msg)
+	}
+	return nil
+}
+
+func (l *ldap) entry(ctx context.Context, dn string) (entry, error) {
+	defer l.deadline(ctx)()
+
+	// SearchRequest ::= [APPLICATION 3] SEQUENCE {
+	//     baseObject LDAPDN, scope ENUMERATED baseObject(0),
+	//     derefAliases ENUMERATED neverDerefAliases(0), sizeLimit INTEGER,
+	//     timeLimit INTEGER, typesOnly BOOLEAN,
+	//     filter present [7] "objectClass", attributes SEQUENCE OF (all) }
+	id, err := l.send(berTLV(0x63,
+		berTLV(0x04, []byte(dn)),
+		berInt(0x0a, 0),
+		berInt(0x0a, 0),
+		berInt(0x02, 0),
+		berInt(0x02, int(ldapTimeout.Seconds())),
+		berTLV(0x01, []byte{0}),
+		berTLV(0x87, []byte("objectClass")),
+		berTLV(0x30),
+	))
+	if err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+
+	var result entry
+	for {
+		resp, err := l.receive(id)
+		if err != nil {
+			return nil, ctxErr(ctx, err)
+		}
+		switch resp.tag {
+		case 0x64:
+			// SearchResultEntry ::= [APPLICATION 4] SEQUENCE {
+			//     objectName LDAPDN, attributes SEQUENCE OF SEQUENCE {
+			//         type AttributeDescription, vals SET OF AttributeValue } }
+			children, err := resp.children()
+			if err != nil || len(children) != 2 {
+				return nil, fmt.Errorf("malformed LDAP search result entry")
+			}
+			attrs, err := children[1].children()
+			if err != nil {
+				return nil, fmt.Errorf("malformed LDAP search result entry: %w", err)
+			}
+			result = entry{}
+			for _, attr := range attrs {
+				parts, err := attr.children()
+				if err != nil || len(parts) != 2 {
+					return nil, fmt.Errorf("malformed LDAP attribute")
+				}
+				values, err := parts[1].children()
+				if err != nil {
+					return nil, fmt.Errorf("malformed LDAP attribute values: %w", err)
+				}
+				name := strings.ToLower(string(parts[0].data))
+				for _, value := range values {
+					result[name] = append(result[name], string(value.data))
+				}
+			}
+		case 0x73:
+			// Ignore search result references.
+		case 0x65:
+			code, msg, err := ldapResult(resp)
+			switch {
+			case err != nil:
+				return nil, err
+			case code == ldapNoSuchObject:
+				return nil, nil
+			case code != ldapSuccess:
+				return nil, fmt.Errorf("failed to read %q: result code %d: %s", dn, code, msg)
+			}
+			return result, nil
+		default:
+			return nil, fmt.Errorf("unexpected LDAP response 0x%x to search request", resp.tag)
+		}
+	}
+}
+
+// send sends the given protocol operation in a new message and returns the
+// ID of the message.
+func (l *ldap) send(op []byte) (int, error) {
+	id := l.nextID
+	l.nextID++
+	// LDAPMessage ::= SEQUENCE { messageID INTEGER, protocolOp }
+	if _, err := l.conn.Write(berTLV(0x30, berInt(0x02, id), op)); err != nil {
+		return 0, fmt.Errorf("failed to send LDAP request: %w", err)
+	}
+	return id, nil
+}
+
+// receive returns the protocol operation of the next message, which must be
+// a response to the message with the given ID.
+func (l *ldap) receive(id int) (ber, error) {
+	msg, err := readBER(l.r)
+	if err != nil {
+		return ber{}, fmt.Errorf("failed to read LDAP response: %w", err)
+	}
+	children, err := msg.children()
+	if err != nil || msg.tag != 0x30 || len(children) < 2 {
+		return ber{}, fmt.Errorf("malformed LDAP message")
+	}
+	if got := children[0].int(); got != id {
+		return ber{}, fmt.Errorf("unexpected LDAP response to message %d, expected %d", got, id)
+	}
+	return children[1], nil
+}
+
+// ldapResult returns the result code and diagnostic message of an LDAPResult.
+func ldapResult(resp ber) (int, string, error) {
+	children, err := resp.children()
+	if err != nil || len(children) < 3 {
+		return 0, "", fmt.Errorf("malformed LDAP result")
+	}
+	return children[0].int(), string(children[2].data), nil
+}
+
+// ber is a BER encoded value, which is either primitive or constructed from
+// other values.
+type ber struct {
+	tag  byte
+	data []byte
+}
+
+func (b ber) int() int {
+	var v int
+	for i, c := range b.data {
+		if i == 0 && c&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func (b ber) children() ([]ber, error) {
+	var children []ber
+	data := b.data
+	for len(data) != 0 {
+		if len(data) < 2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		tag := data[0]
+		length, n, err := berLength(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		data = data[1+n:]
+		if length > len(data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		children = append(children, ber{tag: tag, data: data[:length]})
+		data = data[length:]
+	}
+	return children, nil
+}
+
+// berLength decodes the length at the start of data and returns it with the
+// number of bytes it takes.
+func berLength(data []byte) (int, int, error) {
+	if data[0]&0x80 == 0 {
+		return int(data[0]), 1, nil
+	}
+	n := int(data[0] & 0x7f)
+	if n == 0 || n > 4 {
+		return 0, 0, errors.New("unsupported BER length")
+	}
+	if len(data) < 1+n {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	var length int
+	for _, c := range data[1 : 1+n] {
+		length = length<<8 | int(c)
+	}
+	if length > maxMessageSize {
+		return 0, 0, fmt.Errorf("BER value of %d bytes exceeds the maximum of %d bytes", length, maxMessageSize)
+	}
+	return length, 1 + n, nil
+}
+
+func readBER(r *bufio.Reader) (ber, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return ber{}, err
+	}
+	header := make([]byte, 1, 5)
+	if header[0], err = r.ReadByte(); err != nil {
+		return ber{}, err
+	}
+	if header[0]&0x80 != 0 {
+		extra := make([]byte, header[0]&0x7f)
+		if _, err := io.ReadFull(r, extra); err != nil {
+			return ber{}, err
+		}
+		header = append(header, extra...)
+	}
+	// berLength refuses lengths above maxMessageSize, so a malicious server
+	// can't make the client allocate an arbitrary amount of memory.
+	length, _, err := berLength(header)
+	if err != nil {
+		return ber{}, err
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return ber{}, err
+	}
+	return ber{tag: tag, data: data}, nil
+}
+
+// berTLV encodes a value with the given tag and the concatenation of the
+// given contents.
+func berTLV(tag byte, contents ...[]byte) []byte {
+	var data []byte
+	for _, c := range contents {
+		data = append(data, c...)
+	}
+	out := []byte{tag}
+	switch n := len(data); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x84, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, data...)
+}
+
+// berInt encodes a non-negative integer with the given tag.
+func berInt(tag byte, v int) []byte {
+	data := []byte{byte(v)}
+	for v >>= 8; v != 0; v >>= 8 {
+		data = append([]byte{byte(v)}, data...)
+	}
+	if data[0]&0x80 != 0 {
+		data = append([]byte{0}, data...)
+	}
+	return berTLV(tag, data)
+}
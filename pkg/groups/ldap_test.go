@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package groups
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestBERRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, 0x7f, 0x80, 0xff, 0x100, 0xffff, 0x10000} {
+		data := bytes.Repeat([]byte{0xab}, size)
+		got, err := readBER(bufio.NewReader(bytes.NewReader(berTLV(0x04, data))))
+		if err != nil {
+			t.Fatalf("readBER() of %d bytes error = %v", size, err)
+		}
+		if got.tag != 0x04 || !bytes.Equal(got.data, data) {
+			t.Errorf("readBER() of %d bytes = tag 0x%x, %d bytes", size, got.tag, len(got.data))
+		}
+	}
+
+	for _, v := range []int{0, 1, 0x7f, 0x80, 0xff, 0x100, 1 << 20, 1<<31 - 1} {
+		got, err := readBER(bufio.NewReader(bytes.NewReader(berInt(0x02, v))))
+		if err != nil {
+			t.Fatalf("readBER() of %d error = %v", v, err)
+		}
+		if got.int() != v {
+			t.Errorf("readBER() of %d = %d", v, got.int())
+		}
+	}
+
+	msg := berTLV(0x30, berInt(0x02, 7), berTLV(0x04, []byte("a")), berTLV(0x30))
+	got, err := readBER(bufio.NewReader(bytes.NewReader(msg)))
+	if err != nil {
+		t.Fatalf("readBER() error = %v", err)
+	}
+	children, err := got.children()
+	if err != nil {
+		t.Fatalf("children() error = %v", err)
+	}
+	want := []ber{{tag: 0x02, data: []byte{7}}, {tag: 0x04, data: []byte("a")}, {tag: 0x30, data: []byte{}}}
+	if !reflect.DeepEqual(children, want) {
+		t.Errorf("children() = %v, want %v", children, want)
+	}
+}
+
+func TestReadBERErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"above the maximum size", []byte{0x30, 0x84, 0xff, 0xff, 0xff, 0xff}, "exceeds the maximum"},
+		{"indefinite length", []byte{0x30, 0x80}, "unsupported BER length"},
+		{"length of 5 bytes", []byte{0x30, 0x85, 0, 0, 0, 0, 1}, "unsupported BER length"},
+		{"truncated length", []byte{0x30, 0x82, 0x01}, "unexpected EOF"},
+		{"truncated value", []byte{0x30, 0x03, 0x01}, "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readBER(bufio.NewReader(bytes.NewReader(tt.data)))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readBER() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := (ber{tag: 0x30, data: []byte{0x04, 0x05, 'a'}}).children(); err == nil {
+		t.Errorf("children() of a truncated value succeeded")
+	}
+}
+
+// fakeLDAP is an in-process LDAP server serving the entries of a directory.
+type fakeLDAP struct {
+	entries  ldif
+	password string
+	// hang makes the server stop replying to search requests.
+	hang bool
+
+	binds []string
+}
+
+// serve answers the requests received on conn until it's closed or the
+// client unbinds.
+func (s *fakeLDAP) serve(conn net.Conn) error {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readBER(r)
+		if err != nil {
+			return err
+		}
+		children, err := msg.children()
+		if err != nil || len(children) != 2 {
+			return errors.New("malformed message")
+		}
+		id, op := children[0].int(), children[1]
+		fields, err := op.children()
+		if err != nil {
+			return err
+		}
+
+		reply := func(op []byte) error {
+			_, err := conn.Write(berTLV(0x30, berInt(0x02, id), op))
+			return err
+		}
+		result := func(tag byte, code int, msg string) error {
+			return reply(berTLV(tag, berInt(0x0a, code), berTLV(0x04), berTLV(0x04, []byte(msg))))
+		}
+
+		switch op.tag {
+		case 0x60:
+			dn, password := string(fields[1].data), string(fields[2].data)
+			s.binds = append(s.binds, dn)
+			code := ldapSuccess
+			if password != s.password {
+				code = 49 // invalidCredentials
+			}
+			if err := result(0x61, code, ""); err != nil {
+				return err
+			}
+		case 0x63:
+			if s.hang {
+				continue
+			}
+			dn := string(fields[0].data)
+			e, ok := s.entries[normalizeDN(dn)]
+			if !ok {
+				if err := result(0x65, ldapNoSuchObject, "no such object"); err != nil {
+					return err
+				}
+				continue
+			}
+			var attrs [][]byte
+			for name, values := range e {
+				var vals [][]byte
+				for _, v := range values {
+					vals = append(vals, berTLV(0x04, []byte(v)))
+				}
+				attrs = append(attrs, berTLV(0x30, berTLV(0x04, []byte(name)), berTLV(0x31, vals...)))
+			}
+			if err := reply(berTLV(0x64, berTLV(0x04, []byte(dn)), berTLV(0x30, attrs...))); err != nil {
+				return err
+			}
+			if err := reply(berTLV(0x73, berTLV(0x04, []byte("ldap://other.example.com/")))); err != nil {
+				return err
+			}
+			if err := result(0x65, ldapSuccess, ""); err != nil {
+				return err
+			}
+		case 0x42:
+			return nil
+		default:
+			return errors.New("unexpected request")
+		}
+	}
+}
+
+func startFakeLDAP(t *testing.T, s *fakeLDAP) net.Conn {
+	t.Helper()
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.serve(server)
+	}()
+	t.Cleanup(func() {
+		client.Close()
+		<-done
+	})
+	return client
+}
+
+func TestLDAPGroupMembers(t *testing.T) {
+	entries, err := readLDIF(filepath.Join("testdata", "directory.ldif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeLDAP{entries: entries, password: "secret"}
+
+	l, err := newLDAP(context.Background(), startFakeLDAP(t, s), "cn=team-manager,dc=example,dc=com", "secret")
+	if err != nil {
+		t.Fatalf("newLDAP() error = %v", err)
+	}
+	got, unresolvable, err := groupMembers(context.Background(), l, "cn=sig-datapath,ou=groups,dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("groupMembers() error = %v", err)
+	}
+	if err := l.close(); err != nil {
+		t.Errorf("close() error = %v", err)
+	}
+
+	want := []config.Identity{
+		{ID: "alice", Email: "alice@example.com", EmployeeID: "1001"},
+		{ID: "bob", Email: "bob@example.com", Login: "bob-gh"},
+		{ID: "dave"},
+		{ID: "erin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupMembers() = %+v, want %+v", got, want)
+	}
+	wantUnresolvable := []string{"cn=contractors,ou=groups,dc=example,dc=com"}
+	if !reflect.DeepEqual(unresolvable, wantUnresolvable) {
+		t.Errorf("groupMembers() unresolvable = %v, want %v", unresolvable, wantUnresolvable)
+	}
+	if want := []string{"cn=team-manager,dc=example,dc=com"}; !reflect.DeepEqual(s.binds, want) {
+		t.Errorf("binds = %v, want %v", s.binds, want)
+	}
+}
+
+func TestLDAPBindFailure(t *testing.T) {
+	s := &fakeLDAP{password: "secret"}
+	_, err := newLDAP(context.Background(), startFakeLDAP(t, s), "cn=team-manager,dc=example,dc=com", "wrong")
+	if err == nil || !strings.Contains(err.Error(), "result code 49") {
+		t.Fatalf("newLDAP() error = %v, want result code 49", err)
+	}
+}
+
+func TestLDAPContextCanceled(t *testing.T) {
+	s := &fakeLDAP{hang: true}
+	l, err := newLDAP(context.Background(), startFakeLDAP(t, s), "", "")
+	if err != nil {
+		t.Fatalf("newLDAP() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := l.entry(ctx, "cn=sig-datapath,ou=groups,dc=example,dc=com")
+		errs <- err
+	}()
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("entry() error = %v, want %v", err, context.Canceled)
+	}
+}
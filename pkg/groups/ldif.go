@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package groups
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ldif is a directory read from an LDIF file.
+type ldif map[string]entry
+
+func (l ldif) entry(_ context.Context, dn string) (entry, error) {
+	return l[normalizeDN(dn)], nil
+}
+
+// readLDIF reads the entries of an LDIF file. Change records and values
+// referenced by URLs are not supported.
+func readLDIF(file string) (ldif, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Unfold the lines continued in the next ones, which start with a space.
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, " ") && len(lines) != 0:
+			lines[len(lines)-1] += line[1:]
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	entries := ldif{}
+	var dn string
+	var e entry
+	add := func() {
+		if dn != "" {
+			entries[normalizeDN(dn)] = e
+		}
+		dn, e = "", nil
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			add()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		attr, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", file, i+1, line)
+		}
+		switch {
+		case strings.HasPrefix(value, ":"):
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid base64 value of %q: %w", file, i+1, attr, err)
+			}
+			value = string(decoded)
+		case strings.HasPrefix(value, "<"):
+			return nil, fmt.Errorf("%s:%d: values referenced by URLs are not supported", file, i+1)
+		default:
+			value = strings.TrimSpace(value)
+		}
+
+		attr = strings.ToLower(attr)
+		switch {
+		case attr == "version" && dn == "":
+		case attr == "dn":
+			dn, e = value, entry{}
+		case dn == "":
+			return nil, fmt.Errorf("%s:%d: attribute %q outside of an entry", file, i+1, attr)
+		case attr == "changetype":
+			return nil, fmt.Errorf("%s:%d: change records are not supported", file, i+1)
+		default:
+			e[attr] = append(e[attr], value)
+		}
+	}
+	add()
+	return entries, nil
+}
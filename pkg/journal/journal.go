@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package journal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Kind is the kind of operation applied to GitHub.
+type Kind string
+
+const (
+	KindInviteOrgMember          Kind = "invite-org-member"
+	KindRemoveOrgMember          Kind = "remove-org-member"
+	KindSetOrgMemberRole         Kind = "set-org-member-role"
+	KindCreateTeam               Kind = "create-team"
+	KindDeleteTeam               Kind = "delete-team"
+	KindEditTeam                 Kind = "edit-team"
+	KindRenameTeam               Kind = "rename-team"
+	KindAddTeamMember            Kind = "add-team-member"
+	KindRemoveTeamMember         Kind = "remove-team-member"
+	KindSetTeamMemberRole        Kind = "set-team-member-role"
+	KindUpdateCodeReview         Kind = "update-code-review-assignment"
+	KindSetTeamRepoPermission    Kind = "set-team-repo-permission"
+	KindRemoveTeamRepoPermission Kind = "remove-team-repo-permission"
+	KindSetUserRepoPermission    Kind = "set-user-repo-permission"
+	KindRemoveUserRepoPermission Kind = "remove-user-repo-permission"
+)
+
+// Operation is a single write operation applied to GitHub.
+type Operation struct {
+	Kind       Kind   `json:"kind"`
+	Team       string `json:"team,omitempty"`
+	Member     string `json:"member,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Permission string `json:"permission,omitempty"`
+	Role       string `json:"role,omitempty"`
+
+	// Previous is the state in GitHub before this operation was applied. It
+	// is used to compute the inverse of this operation.
+	Previous *State `json:"previous,omitempty"`
+}
+
+// State is the state of the entity changed by an operation, before it was
+// changed.
+type State struct {
+	// Permission is the previous permission of a team or user in a
+	// repository, empty if they had none.
+	Permission string `json:"permission,omitempty"`
+
+	// Role is the previous role of a member in a team or in the
+	// organization.
+	Role string `json:"role,omitempty"`
+
+	// Name is the previous name of a renamed team.
+	Name string `json:"name,omitempty"`
+
+	// Description, Privacy and ParentTeam are the previous settings of a
+	// team.
+	Description string             `json:"description,omitempty"`
+	Privacy     config.TeamPrivacy `json:"privacy,omitempty"`
+	ParentTeam  string             `json:"parentTeam,omitempty"`
+
+	// CodeReviewAssignment is the previous code review assignment of a team.
+	// Excluded members are never known since GitHub doesn't provide them.
+	CodeReviewAssignment *config.CodeReviewAssignment `json:"codeReviewAssignment,omitempty"`
+
+	// Members and Maintainers are the previous members of a team.
+	Members     []string `json:"members,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+
+	// Repositories maps each repository to the previous permission of a
+	// team in it.
+	Repositories map[string]string `json:"repositories,omitempty"`
+
+	// Children are the previous child teams of a team, which GitHub deletes
+	// along with their parent.
+	Children map[string]*State `json:"children,omitempty"`
+
+	// Teams are the teams a removed member previously belonged to.
+	Teams []string `json:"teams,omitempty"`
+}
+
+func (o Operation) String() string {
+	s := string(o.Kind)
+	if o.Team != "" {
+		s += fmt.Sprintf(" team=%q", o.Team)
+	}
+	if o.Member != "" {
+		s += fmt.Sprintf(" member=%q", o.Member)
+	}
+	if o.Repository != "" {
+		s += fmt.Sprintf(" repository=%q", o.Repository)
+	}
+	if o.Permission != "" {
+		s += fmt.Sprintf(" permission=%q", o.Permission)
+	}
+	if o.Role != "" {
+		s += fmt.Sprintf(" role=%q", o.Role)
+	}
+	if o.Kind == KindRenameTeam && o.Previous != nil {
+		s += fmt.Sprintf(" previous-name=%q", o.Previous.Name)
+	}
+	return s
+}
+
+// Options are the options a push was started with, so that it can be resumed
+// with the same ones.
+type Options struct {
+	Repositories bool `json:"repositories"`
+	Members      bool `json:"members"`
+	Teams        bool `json:"teams"`
+
+	// RollbackOf is set to the ID of the run being reverted if this run is
+	// a rollback.
+	RollbackOf string `json:"rollbackOf,omitempty"`
+}
+
+type event string
+
+const (
+	eventStart     event = "start"
+	eventOperation event = "operation"
+	eventFinish    event = "finish"
+)
+
+// entry is a single line of the journal file.
+type entry struct {
+	Run       string     `json:"run"`
+	Time      time.Time  `json:"time"`
+	Event     event      `json:"event"`
+	Options   *Options   `json:"options,omitempty"`
+	Operation *Operation `json:"operation,omitempty"`
+}
+
+// Run is a single push recorded in the journal.
+type Run struct {
+	ID         string
+	Started    time.Time
+	Finished   bool
+	Options    Options
+	Operations []Operation
+}
+
+// Journal is an append-only file that records every operation applied to
+// GitHub by each push.
+type Journal struct {
+	path string
+	runs []*Run
+
+	// partial is set if the last line of the journal file was cut short, in
+	// which case it's dropped by the next append. size is the size of the
+	// file without it.
+	partial bool
+	size    int64
+
+	// current is the run being recorded.
+	current *Run
+}
+
+// Open reads the journal stored in the given path. A journal that does not
+// exist yet is considered empty, and a last entry that was cut short is
+// ignored.
+func Open(path string) (*Journal, error) {
+	j := &Journal{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return j, nil
+		}
+		return nil, err
+	}
+
+	runs := map[string]*Run{}
+	for line := 1; len(data) != 0; line++ {
+		i := bytes.IndexByte(data, '\n')
+		if i == -1 {
+			// Entries are written along with their newline, so the last
+			// one was cut short, for example if team-manager was killed
+			// while recording it.
+			j.partial = true
+			break
+		}
+		var e entry
+		if err := json.Unmarshal(data[:i], &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		data = data[i+1:]
+		j.size += int64(i + 1)
+
+		run, ok := runs[e.Run]
+		if !ok {
+			run = &Run{ID: e.Run, Started: e.Time}
+			runs[e.Run] = run
+			j.runs = append(j.runs, run)
+		}
+		switch e.Event {
+		case eventStart:
+			if e.Options != nil {
+				run.Options = *e.Options
+			}
+		case eventOperation:
+			if e.Operation != nil {
+				run.Operations = append(run.Operations, *e.Operation)
+			}
+		case eventFinish:
+			run.Finished = true
+		}
+	}
+
+	return j, nil
+}
+
+// Runs returns all runs recorded in the journal, from the oldest to the most
+// recent one.
+func (j *Journal) Runs() []*Run {
+	return j.runs
+}
+
+// Run returns the run with the given ID.
+func (j *Journal) Run(id string) (*Run, error) {
+	for _, run := range j.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("run %q not found in journal %q", id, j.path)
+}
+
+// Unfinished returns the runs that did not finish, from the oldest to the most
+// recent one.
+func (j *Journal) Unfinished() []*Run {
+	var unfinished []*Run
+	for _, run := range j.runs {
+		if !run.Finished {
+			unfinished = append(unfinished, run)
+		}
+	}
+	return unfinished
+}
+
+// Rollbacks returns the rollbacks of the run with the given ID, from the
+// oldest to the most recent one.
+func (j *Journal) Rollbacks(id string) []*Run {
+	var rollbacks []*Run
+	for _, run := range j.runs {
+		if run.Options.RollbackOf == id {
+			rollbacks = append(rollbacks, run)
+		}
+	}
+	return rollbacks
+}
+
+// RolledBack returns true if the run with the given ID was entirely reverted
+// by a rollback.
+func (j *Journal) RolledBack(id string) bool {
+	for _, rollback := range j.Rollbacks(id) {
+		if rollback.Finished {
+			return true
+		}
+	}
+	return false
+}
+
+// Start starts recording a new run with the given options. The ID of the run
+// is its start time with a random suffix, so that runs started in the same
+// second get different IDs.
+func (j *Journal) Start(opts Options) (*Run, error) {
+	now := time.Now().UTC()
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	run := &Run{
+		ID:      now.Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix),
+		Started: now,
+		Options: opts,
+	}
+	if _, err := j.Run(run.ID); err == nil {
+		return nil, fmt.Errorf("run %q already exists in journal %q", run.ID, j.path)
+	}
+	if err := j.append(entry{Run: run.ID, Time: now, Event: eventStart, Options: &opts}); err != nil {
+		return nil, err
+	}
+	j.runs = append(j.runs, run)
+	j.current = run
+	return run, nil
+}
+
+// Resume continues recording the given run.
+func (j *Journal) Resume(run *Run) {
+	j.current = run
+}
+
+// Record appends the given operation to the current run.
+func (j *Journal) Record(op Operation) error {
+	if j.current == nil {
+		return fmt.Errorf("no run started in journal %q", j.path)
+	}
+	if err := j.append(entry{Run: j.current.ID, Time: time.Now().UTC(), Event: eventOperation, Operation: &op}); err != nil {
+		return err
+	}
+	j.current.Operations = append(j.current.Operations, op)
+	return nil
+}
+
+// Finish marks the current run as finished.
+func (j *Journal) Finish() error {
+	if j.current == nil {
+		return fmt.Errorf("no run started in journal %q", j.path)
+	}
+	if err := j.append(entry{Run: j.current.ID, Time: time.Now().UTC(), Event: eventFinish}); err != nil {
+		return err
+	}
+	j.current.Finished = true
+	j.current = nil
+	return nil
+}
+
+func (j *Journal) append(e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if j.partial {
+		if err := os.Truncate(j.path, j.size); err != nil {
+			return err
+		}
+		j.partial = false
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
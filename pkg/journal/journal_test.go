@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team-assignments.yaml.journal")
+
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() of a missing journal error = %v", err)
+	}
+	if len(j.Runs()) != 0 {
+		t.Fatalf("Open() of a missing journal returned %d runs", len(j.Runs()))
+	}
+	if err := j.Record(Operation{Kind: KindCreateTeam, Team: "sig-foo"}); err == nil {
+		t.Errorf("Record() without a run succeeded")
+	}
+	if err := j.Finish(); err == nil {
+		t.Errorf("Finish() without a run succeeded")
+	}
+
+	// A finished push, an interrupted one and an interrupted rollback of the
+	// first one.
+	finished, err := j.Start(Options{Teams: true})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	ops := []Operation{
+		{Kind: KindCreateTeam, Team: "sig-foo"},
+		{Kind: KindAddTeamMember, Team: "sig-foo", Member: "aanm", Role: "member"},
+	}
+	for _, op := range ops {
+		if err := j.Record(op); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := j.Finish(); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	interrupted, err := j.Start(Options{Members: true, Repositories: true})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	removal := Operation{Kind: KindRemoveOrgMember, Member: "bob", Previous: &State{Role: "member", Teams: []string{"sig-foo"}}}
+	if err := j.Record(removal); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	rollback, err := j.Start(Options{RollbackOf: finished.ID})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if rollback.ID == interrupted.ID || interrupted.ID == finished.ID {
+		t.Errorf("Start() returned the same ID twice: %q, %q, %q", finished.ID, interrupted.ID, rollback.ID)
+	}
+
+	j, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	runs := j.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("Runs() returned %d runs, want 3", len(runs))
+	}
+	if got := runs[0]; got.ID != finished.ID || !got.Finished || !reflect.DeepEqual(got.Operations, ops) || got.Options != (Options{Teams: true}) {
+		t.Errorf("Runs()[0] = %+v, want finished run %q with %+v", got, finished.ID, ops)
+	}
+	if got := runs[1]; got.ID != interrupted.ID || got.Finished || !reflect.DeepEqual(got.Operations, []Operation{removal}) {
+		t.Errorf("Runs()[1] = %+v, want unfinished run %q with %+v", got, interrupted.ID, removal)
+	}
+	if got, err := j.Run(interrupted.ID); err != nil || got != runs[1] {
+		t.Errorf("Run(%q) = %v, %v", interrupted.ID, got, err)
+	}
+	if _, err := j.Run("unknown"); err == nil {
+		t.Errorf("Run() of an unknown ID succeeded")
+	}
+
+	if got := j.Unfinished(); !reflect.DeepEqual(got, runs[1:]) {
+		t.Errorf("Unfinished() = %v, want %v", got, runs[1:])
+	}
+	if got := j.Rollbacks(finished.ID); !reflect.DeepEqual(got, runs[2:]) {
+		t.Errorf("Rollbacks() = %v, want %v", got, runs[2:])
+	}
+	if j.RolledBack(finished.ID) {
+		t.Errorf("RolledBack() of a push with an unfinished rollback = true")
+	}
+
+	// Resuming continues recording the given run.
+	j.Resume(runs[2])
+	if err := j.Record(Operation{Kind: KindDeleteTeam, Team: "sig-foo"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := j.Finish(); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	j, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := j.Unfinished(); len(got) != 1 || got[0].ID != interrupted.ID {
+		t.Errorf("Unfinished() = %v, want only %q", got, interrupted.ID)
+	}
+	if got := j.Runs()[2]; !got.Finished || len(got.Operations) != 1 {
+		t.Errorf("resumed run = %+v, want finished with 1 operation", got)
+	}
+	if !j.RolledBack(finished.ID) {
+		t.Errorf("RolledBack() of a push with a finished rollback = false")
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	data := `{"run":"r1","time":"2024-02-13T10:00:02Z","event":"start","options":{"repositories":false,"members":true,"teams":false}}
+{"run":"r1","time":"2024-02-13T10:00:03Z","event":"operation","operation":{"kind":"remove-org-member","member":"bob"}}
+{"run":"r1","time":"2024-02-13T10:00:04Z","event":"operation","operation":{"kind":"remove-org-m`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	runs := j.Unfinished()
+	if len(runs) != 1 || len(runs[0].Operations) != 1 {
+		t.Fatalf("Unfinished() = %+v, want 1 run with 1 operation", runs)
+	}
+
+	// The truncated line is replaced by the next entry.
+	j.Resume(runs[0])
+	if err := j.Record(Operation{Kind: KindRemoveOrgMember, Member: "carol"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	j, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() after recording error = %v", err)
+	}
+	want := []Operation{
+		{Kind: KindRemoveOrgMember, Member: "bob"},
+		{Kind: KindRemoveOrgMember, Member: "carol"},
+	}
+	if got := j.Runs()[0].Operations; !reflect.DeepEqual(got, want) {
+		t.Errorf("Operations = %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	data := `{"run":"r1","time":"2024-02-13T10:00:02Z","event":"start"}
+not json
+{"run":"r1","time":"2024-02-13T10:00:04Z","event":"finish"}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), path+":2:") {
+		t.Errorf("Open() error = %v, want an error on line 2", err)
+	}
+}
+
+func TestOperationString(t *testing.T) {
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{Operation{Kind: KindCreateTeam, Team: "sig-foo"}, `create-team team="sig-foo"`},
+		{Operation{Kind: KindSetUserRepoPermission, Member: "aanm", Repository: "cilium", Permission: "WRITE"}, `set-user-repo-permission member="aanm" repository="cilium" permission="WRITE"`},
+		{Operation{Kind: KindRenameTeam, Team: "sig-bar", Previous: &State{Name: "sig-foo"}}, `rename-team team="sig-bar" previous-name="sig-foo"`},
+	}
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package migration migrates configuration files written by older versions
+// of team-manager to the current version of the file format.
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Step migrates a configuration file from version From to version From+1.
+type Step struct {
+	From        int
+	Description string
+
+	// Apply modifies the root mapping of the configuration file in place.
+	Apply func(root *yamlv3.Node) error
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("version %d to %d: %s", s.From, s.From+1, s.Description)
+}
+
+// steps are the registered migration steps, indexed by the version they
+// migrate from.
+var steps []Step
+
+// register registers the migration step from the next version.
+func register(step Step) {
+	if step.From != len(steps) {
+		panic(fmt.Sprintf("migration from version %d registered out of order, expected version %d", step.From, len(steps)))
+	}
+	if step.From >= config.CurrentVersion {
+		panic(fmt.Sprintf("migration from version %d registered, but current version is %d", step.From, config.CurrentVersion))
+	}
+	steps = append(steps, step)
+}
+
+// Version returns the version of the given configuration file. Files without
+// version are version 0.
+func Version(file string, data []byte) (int, error) {
+	root, err := decode(data)
+	if err != nil || root == nil {
+		return 0, err
+	}
+	return version(file, root)
+}
+
+// Migrate migrates the given configuration file to the current version and
+// returns the migrated file and the steps applied. Files with a newer version
+// than the current one are refused.
+func Migrate(file string, data []byte) ([]byte, []Step, error) {
+	if len(steps) != config.CurrentVersion {
+		panic(fmt.Sprintf("%d migration steps registered, expected %d", len(steps), config.CurrentVersion))
+	}
+
+	root, err := decode(data)
+	if err != nil || root == nil {
+		return data, nil, err
+	}
+	v, err := version(file, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v == config.CurrentVersion {
+		return data, nil, nil
+	}
+
+	var applied []Step
+	for ; v < config.CurrentVersion; v++ {
+		step := steps[v]
+		if err := step.Apply(root); err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate %s from %s: %w", file, step, err)
+		}
+		applied = append(applied, step)
+	}
+	setVersion(root, v)
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), applied, nil
+}
+
+// decode returns the root mapping of the configuration file, or nil if the
+// file is empty.
+func decode(data []byte) (*yamlv3.Node, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+func version(file string, root *yamlv3.Node) (int, error) {
+	node := value(root, "version")
+	if node == nil {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(node.Value)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s:%d:%d: invalid version %q", file, node.Line, node.Column, node.Value)
+	}
+	if v > config.CurrentVersion {
+		return 0, fmt.Errorf("%s has version %d of the configuration format, but this team-manager only supports up to version %d, please upgrade team-manager", file, v, config.CurrentVersion)
+	}
+	return v, nil
+}
+
+func setVersion(root *yamlv3.Node, v int) {
+	if node := value(root, "version"); node != nil {
+		node.SetString(strconv.Itoa(v))
+		node.Tag = "!!int"
+		return
+	}
+	// The version is the first field of the file.
+	root.Content = append([]*yamlv3.Node{
+		{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "version"},
+		{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.Itoa(v)},
+	}, root.Content...)
+}
+
+// value returns the value of the given key of a mapping, or nil if not found.
+func value(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	if mapping == nil || mapping.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// entries calls fn for every key and value of a mapping.
+func entries(mapping *yamlv3.Node, fn func(key, value *yamlv3.Node)) {
+	if mapping == nil || mapping.Kind != yamlv3.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		fn(mapping.Content[i], mapping.Content[i+1])
+	}
+}
+
+// teams calls fn for every team of the configuration file, including child
+// teams.
+func teams(root *yamlv3.Node, fn func(team *yamlv3.Node)) {
+	var walk func(teams *yamlv3.Node)
+	walk = func(teams *yamlv3.Node) {
+		entries(teams, func(_, team *yamlv3.Node) {
+			fn(team)
+			walk(value(team, "children"))
+		})
+	}
+	walk(value(root, "teams"))
+}
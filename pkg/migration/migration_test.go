@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestMigrate(t *testing.T) {
+	current := "version: 1\norganization: cilium\nmembers:\n  aanm:\n    slackID: U1\n"
+
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		steps   int
+		wantErr string
+	}{
+		{
+			name: "version-less file is migrated to v1",
+			data: `organization: cilium
+members:
+  aanm:
+    id: MDQ6VXNlcjU3MTQwNjY=
+    slackId: U3Z10R6HW
+teams:
+  parent:
+    privacy: secret
+    children:
+      child:
+        privacy: visible
+`,
+			want: `version: 1
+organization: cilium
+members:
+  aanm:
+    id: MDQ6VXNlcjU3MTQwNjY=
+    slackID: U3Z10R6HW
+teams:
+  parent:
+    privacy: SECRET
+    children:
+      child:
+        privacy: VISIBLE
+`,
+			steps: 1,
+		},
+		{
+			name:  "version 0 is migrated in place",
+			data:  "version: 0\norganization: cilium\n",
+			want:  "version: 1\norganization: cilium\n",
+			steps: 1,
+		},
+		{
+			name:  "existing slackID is not overwritten",
+			data:  "members:\n  aanm:\n    slackId: old\n    slackID: new\n",
+			want:  "version: 1\nmembers:\n  aanm:\n    slackId: old\n    slackID: new\n",
+			steps: 1,
+		},
+		{
+			name: "current version is returned unchanged",
+			data: current,
+			want: current,
+		},
+		{
+			name:    "newer version is refused",
+			data:    "version: 2\norganization: cilium\n",
+			wantErr: "only supports up to version 1",
+		},
+		{
+			name:    "negative version",
+			data:    "version: -1\n",
+			wantErr: `test.yaml:1:10: invalid version "-1"`,
+		},
+		{
+			name:    "non-numeric version",
+			data:    "version: one\n",
+			wantErr: `test.yaml:1:10: invalid version "one"`,
+		},
+		{
+			name: "empty document",
+			data: "",
+			want: "",
+		},
+		{
+			name: "comment-only document",
+			data: "# nothing here\n",
+			want: "# nothing here\n",
+		},
+		{
+			name:    "invalid YAML",
+			data:    "organization: [cilium\n",
+			wantErr: "did not find expected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, steps, err := Migrate("test.yaml", []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Migrate() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Migrate() output mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if len(steps) != tt.steps {
+				t.Errorf("Migrate() applied %d steps, want %d", len(steps), tt.steps)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    int
+		wantErr bool
+	}{
+		{data: "organization: cilium\n", want: 0},
+		{data: "", want: 0},
+		{data: "version: 1\n", want: config.CurrentVersion},
+		{data: "version: 99\n", wantErr: true},
+		{data: "version: -3\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Version("test.yaml", []byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Version(%q) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Version(%q) = %d, want %d", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestStepsRegistered(t *testing.T) {
+	if len(steps) != config.CurrentVersion {
+		t.Fatalf("%d migration steps registered, want %d", len(steps), config.CurrentVersion)
+	}
+	for i, step := range steps {
+		if step.From != i {
+			t.Errorf("step %d migrates from version %d", i, step.From)
+		}
+	}
+}
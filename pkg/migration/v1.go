@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package migration
+
+import (
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Version 1 is validated against a schema, which only accepts the privacy of
+// teams in upper case, as written by 'pull', and the 'slackID' field of
+// members, which was documented as 'slackId' and silently ignored.
+func init() {
+	register(Step{
+		From:        0,
+		Description: "upper case team privacy and rename the 'slackId' field of members to 'slackID'",
+		Apply: func(root *yamlv3.Node) error {
+			teams(root, func(team *yamlv3.Node) {
+				if privacy := value(team, "privacy"); privacy != nil {
+					privacy.Value = strings.ToUpper(privacy.Value)
+				}
+			})
+			entries(value(root, "members"), func(_, member *yamlv3.Node) {
+				entries(member, func(key, _ *yamlv3.Node) {
+					if key.Value == "slackId" && value(member, "slackID") == nil {
+						key.Value = "slackID"
+					}
+				})
+			})
+			return nil
+		},
+	})
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package persistence
+
+import (
+	"os"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// LocateFindings sets the file, line and column of the given findings from
+// their path in the given configuration file. Findings whose path can't be
+// fully resolved, for example team members set by an override file, are
+// located at the closest parent found.
+func LocateFindings(file string, findings []config.Finding) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	for i := range findings {
+		findings[i].File = file
+		if node := lookup(&root, findings[i].Path); node != nil {
+			findings[i].Line = node.Line
+			findings[i].Column = node.Column
+		}
+	}
+	return nil
+}
+
+// lookup returns the deepest node found following the given path.
+func lookup(node *yamlv3.Node, path []string) *yamlv3.Node {
+	if node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	var found *yamlv3.Node
+	for _, elem := range path {
+		next := child(node, elem)
+		if next == nil {
+			break
+		}
+		found, node = next.key, next.value
+	}
+	return found
+}
+
+type entry struct {
+	// key is the node reported as the location of the entry, the key of
+	// mappings or the item of sequences.
+	key, value *yamlv3.Node
+}
+
+// child returns the entry of the mapping with the given key, or the item of
+// the sequence with the given value, or with the given login for sequences
+// of mappings.
+func child(node *yamlv3.Node, elem string) *entry {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == elem {
+				return &entry{key: node.Content[i], value: node.Content[i+1]}
+			}
+		}
+	case yamlv3.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yamlv3.ScalarNode && item.Value == elem {
+				return &entry{key: item, value: item}
+			}
+			if login := child(item, "login"); item.Kind == yamlv3.MappingNode && login != nil && login.value.Value == elem {
+				return &entry{key: item, value: item}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+const locateConfig = `organization: cilium
+members:
+  alice:
+    id: U1
+teams:
+  parent:
+    members:
+    - alice
+    - bob
+    children:
+      child:
+        members:
+        - login: carol
+          until: "2030-01-02"
+`
+
+func TestLocateFindings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "team-assignments.yaml")
+	if err := os.WriteFile(file, []byte(locateConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		path         []string
+		line, column int
+	}{
+		{
+			name:   "mapping key",
+			path:   []string{"members", "alice"},
+			line:   3,
+			column: 3,
+		},
+		{
+			name:   "sequence item",
+			path:   []string{"teams", "parent", "members", "bob"},
+			line:   9,
+			column: 7,
+		},
+		{
+			name:   "sequence item with a login",
+			path:   []string{"teams", "parent", "children", "child", "members", "carol"},
+			line:   13,
+			column: 11,
+		},
+		{
+			name:   "closest parent",
+			path:   []string{"teams", "parent", "maintainers", "alice"},
+			line:   6,
+			column: 3,
+		},
+		{
+			name: "unknown",
+			path: []string{"outsideCollaborators", "eve"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := []config.Finding{{Rule: "rule", Message: "message", Path: tt.path}}
+			if err := LocateFindings(file, findings); err != nil {
+				t.Fatalf("LocateFindings() error = %v", err)
+			}
+			want := []config.Finding{{
+				Rule:    "rule",
+				Message: "message",
+				Path:    tt.path,
+				File:    file,
+				Line:    tt.line,
+				Column:  tt.column,
+			}}
+			if !reflect.DeepEqual(findings, want) {
+				t.Errorf("LocateFindings() =\n%+v\nwant\n%+v", findings, want)
+			}
+		})
+	}
+
+	if err := LocateFindings(filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Errorf("LocateFindings() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+var (
+	configSchema   = sync.OnceValue(config.ConfigSchema)
+	overrideSchema = sync.OnceValue(config.OverrideSchema)
+)
+
+// validateSchema validates the YAML document of the given file against the
+// schema and returns all problems found, with their location.
+func validateSchema(file string, data []byte, schema *config.Schema) error {
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	v := schemaValidator{file: file, defs: schema.Defs}
+	v.validate(root.Content[0], schema, "")
+	return errors.Join(v.errs...)
+}
+
+type schemaValidator struct {
+	file string
+	defs map[string]*config.Schema
+	errs []error
+}
+
+func (v *schemaValidator) errorf(node *yamlv3.Node, path, format string, a ...interface{}) {
+	if path == "" {
+		path = "."
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s:%d:%d: %s: %s", v.file, node.Line, node.Column, path, fmt.Sprintf(format, a...)))
+}
+
+func (v *schemaValidator) resolve(s *config.Schema) *config.Schema {
+	if s.Ref != "" {
+		return v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+	return s
+}
+
+func (v *schemaValidator) validate(node *yamlv3.Node, s *config.Schema, path string) {
+	if node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	// Empty values are decoded as the zero value of any type.
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	s = v.resolve(s)
+
+	if len(s.OneOf) != 0 {
+		var expected []string
+		var matching []error
+		for _, alternative := range s.OneOf {
+			sub := schemaValidator{file: v.file, defs: v.defs}
+			sub.validate(node, alternative, path)
+			if len(sub.errs) == 0 {
+				return
+			}
+			expected = append(expected, describe(alternative))
+			if kind(v.resolve(alternative)) == node.Kind {
+				matching = sub.errs
+			}
+		}
+		// Report the problems of the alternative of the same kind, which
+		// are more precise.
+		if matching != nil {
+			v.errs = append(v.errs, matching...)
+			return
+		}
+		v.errorf(node, path, "expected %s", strings.Join(expected, " or "))
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yamlv3.MappingNode {
+			v.errorf(node, path, "expected a mapping")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := strings.TrimPrefix(path+"."+key.Value, ".")
+			if s.PropertyNames != nil && !slices.Contains(s.PropertyNames.Enum, key.Value) {
+				v.errorf(key, path, "invalid key %q, valid keys are %s", key.Value, strings.Join(s.PropertyNames.Enum, ", "))
+				continue
+			}
+			if property, ok := s.Properties[key.Value]; ok {
+				v.validate(value, property, keyPath)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case *config.Schema:
+				v.validate(value, additional, keyPath)
+			default:
+				v.errorf(key, path, "unknown field %q", key.Value)
+			}
+		}
+	case "array":
+		if node.Kind != yamlv3.SequenceNode {
+			v.errorf(node, path, "expected a list")
+			return
+		}
+		for i, item := range node.Content {
+			v.validate(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		if node.Kind != yamlv3.ScalarNode {
+			v.errorf(node, path, "expected a string")
+			return
+		}
+		if len(s.Enum) != 0 && !slices.Contains(s.Enum, node.Value) {
+			v.errorf(node, path, "invalid value %q, valid values are %s", node.Value, strings.Join(s.Enum, ", "))
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(node.Value) {
+			v.errorf(node, path, "invalid value %q, expected to match %q", node.Value, s.Pattern)
+		}
+	case "integer":
+		if node.Kind != yamlv3.ScalarNode || node.Tag != "!!int" {
+			v.errorf(node, path, "expected an integer")
+		}
+	case "boolean":
+		if node.Kind != yamlv3.ScalarNode || node.Tag != "!!bool" {
+			v.errorf(node, path, "expected a boolean")
+		}
+	}
+}
+
+// describe returns a short description of the values valid for the schema.
+func describe(s *config.Schema) string {
+	if s.Ref != "" {
+		return strings.TrimPrefix(s.Ref, "#/$defs/")
+	}
+	return "a " + s.Type
+}
+
+// kind returns the kind of YAML node valid for the schema.
+func kind(s *config.Schema) yamlv3.Kind {
+	switch s.Type {
+	case "object":
+		return yamlv3.MappingNode
+	case "array":
+		return yamlv3.SequenceNode
+	default:
+		return yamlv3.ScalarNode
+	}
+}
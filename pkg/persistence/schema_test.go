@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadStateSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		overrides string
+		// wantErrs are the errors, without the name of the file.
+		wantErrs []string
+	}{
+		{
+			name: "valid",
+			config: `version: 1
+organization: cilium
+members:
+  alice:
+    id: U1
+    role: admin
+teams:
+  parent:
+    members:
+    - alice
+    - login: bob
+      until: "2030-01-02"
+repositories:
+  cilium:
+    WRITE:
+    - parent
+`,
+			overrides: `teams:
+  parent:
+    members:
+    - carol
+`,
+		},
+		{
+			name:   "empty",
+			config: "",
+		},
+		{
+			name: "invalid values",
+			config: `version: 1
+memebrs: {}
+members:
+  alice:
+    role: owner
+teams:
+  parent:
+    members: alice
+  child:
+    members:
+    - login: bob
+      until: tomorrow
+repositories:
+  cilium:
+    OWNER:
+    - parent
+`,
+			wantErrs: []string{
+				`2:1: .: unknown field "memebrs"`,
+				`5:11: members.alice.role: invalid value "owner", valid values are admin, member`,
+				`8:14: teams.parent.members: expected a list`,
+				`12:14: teams.child.members[0].until: invalid value "tomorrow", expected to match "^[0-9]{4}-[0-9]{2}-[0-9]{2}$"`,
+				`15:5: repositories.cilium: invalid key "OWNER", valid keys are ADMIN, MAINTAIN, WRITE, TRIAGE, READ, USER-ADMIN, USER-MAINTAIN, USER-WRITE, USER-TRIAGE, USER-READ`,
+			},
+		},
+		{
+			name: "member neither a login nor a mapping",
+			config: `version: 1
+teams:
+  parent:
+    members:
+    - [alice]
+`,
+			wantErrs: []string{
+				`5:7: teams.parent.members[0]: expected a string or TeamMember`,
+			},
+		},
+		{
+			name:      "invalid overrides",
+			config:    "version: 1\n",
+			overrides: "teams:\n  parent:\n    maintainers: [alice]\n",
+			wantErrs: []string{
+				`3:5: teams.parent: unknown field "maintainers"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "team-assignments.yaml")
+			if err := os.WriteFile(file, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var overrides string
+			if tt.overrides != "" {
+				overrides = filepath.Join(dir, "overrides.yaml")
+				if err := os.WriteFile(overrides, []byte(tt.overrides), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := LoadState(file, overrides)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("LoadState() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("LoadState() succeeded, want errors %q", tt.wantErrs)
+			}
+			prefix := file + ":"
+			if tt.overrides != "" {
+				prefix = overrides + ":"
+			}
+			var want []string
+			for _, wantErr := range tt.wantErrs {
+				want = append(want, prefix+wantErr)
+			}
+			if got := err.Error(); got != strings.Join(want, "\n") {
+				t.Errorf("LoadState() error =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+			}
+		})
+	}
+}
@@ -15,9 +15,12 @@
 package persistence
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/migration"
 
 	"github.com/google/renameio"
 	"gopkg.in/yaml.v2"
@@ -28,9 +31,31 @@ func StoreState(file string, cfg *config.Config) error {
 		return err
 	}
 
-	config.SortConfig(cfg)
+	data, err := MarshalState(cfg)
+	if err != nil {
+		return err
+	}
+
+	return renameio.WriteFile(file, data, 0o666)
+}
+
+// MarshalState returns the canonical form of the configuration file, as
+// written by StoreState. cfg is sorted, and stored with the current version
+// without changing the version of cfg itself.
+func MarshalState(cfg *config.Config) ([]byte, error) {
+	if err := config.SortConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	stored := *cfg
+	stored.Version = config.CurrentVersion
+	return yaml.Marshal(&stored)
+}
 
-	data, err := yaml.Marshal(cfg)
+// StoreOverrides stores the team overrides of cfg into the given override
+// file.
+func StoreOverrides(file string, cfg *config.Config) error {
+	data, err := MarshalOverrides(cfg.TeamOverrides)
 	if err != nil {
 		return err
 	}
@@ -38,27 +63,72 @@ func StoreState(file string, cfg *config.Config) error {
 	return renameio.WriteFile(file, data, 0o666)
 }
 
+// MarshalOverrides returns the canonical form of the override file, as
+// written by StoreOverrides.
+func MarshalOverrides(overrides map[string]*config.OverrideTeamConfig) ([]byte, error) {
+	return yaml.Marshal(config.OverrideConfig{Teams: overrides})
+}
+
+// CanonicalFiles returns the current and the canonical content of the given
+// configuration file and, if set, override file, i.e. their content once cfg,
+// loaded from them, is stored by StoreState and StoreOverrides. cfg is sorted.
+func CanonicalFiles(file, overrides string, cfg *config.Config) (current, canonical map[string][]byte, err error) {
+	current = map[string][]byte{}
+	canonical = map[string][]byte{}
+
+	if current[file], err = os.ReadFile(file); err != nil {
+		return nil, nil, err
+	}
+	if canonical[file], err = MarshalState(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	if len(overrides) == 0 {
+		return current, canonical, nil
+	}
+	if current[overrides], err = os.ReadFile(overrides); err != nil {
+		return nil, nil, err
+	}
+	if canonical[overrides], err = MarshalOverrides(cfg.TeamOverrides); err != nil {
+		return nil, nil, err
+	}
+	return current, canonical, nil
+}
+
+// LoadState loads the configuration file and, if set, the override file. Both
+// files are validated against their schema. Configuration files newer than
+// the version supported are refused.
 func LoadState(file, overrides string) (*config.Config, error) {
-	f, err := os.OpenFile(file, os.O_RDONLY, 0440)
+	data, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	// Files of older versions are migrated in memory, and stored with the
+	// current version.
+	data, _, err = migration.Migrate(file, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchema(file, data, configSchema()); err != nil {
+		return nil, err
+	}
 
 	storedConfig := config.Config{}
-	err = yaml.NewDecoder(f).Decode(&storedConfig)
+	err = yaml.Unmarshal(data, &storedConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(overrides) > 0 {
-		o, err := os.OpenFile(overrides, os.O_RDONLY, 0440)
+		data, err := os.ReadFile(overrides)
 		if err != nil {
 			return nil, err
 		}
-		defer o.Close()
+		if err := validateSchema(overrides, data, overrideSchema()); err != nil {
+			return nil, err
+		}
 		storedOverrides := config.OverrideConfig{}
-		err = yaml.NewDecoder(o).Decode(&storedOverrides)
+		err = yaml.Unmarshal(data, &storedOverrides)
 		if err != nil {
 			return nil, err
 		}
@@ -95,3 +165,32 @@ func LoadOverrides(file string) (*config.Config, error) {
 
 	return &storedConfig, nil
 }
+
+// LoadOrganizations loads the file with the configuration of several
+// organizations. The paths of their files are made relative to the current
+// directory.
+func LoadOrganizations(file string) (*config.Organizations, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	orgs := config.Organizations{}
+	if err := yaml.UnmarshalStrict(data, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if err := orgs.Check(); err != nil {
+		return nil, fmt.Errorf("invalid organizations file %s: %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	for name, files := range orgs.Organizations {
+		if files.ConfigFilename != "" && !filepath.IsAbs(files.ConfigFilename) {
+			files.ConfigFilename = filepath.Join(dir, files.ConfigFilename)
+		}
+		if files.OverrideFilename != "" && !filepath.IsAbs(files.OverrideFilename) {
+			files.OverrideFilename = filepath.Join(dir, files.OverrideFilename)
+		}
+		orgs.Organizations[name] = files
+	}
+	return &orgs, nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestLoadOrganizations(t *testing.T) {
+	tests := []struct {
+		name    string
+		orgs    string
+		want    map[string]config.OrganizationFiles
+		wantErr string
+	}{
+		{
+			name: "relative and absolute paths",
+			orgs: `people:
+  alice:
+    logins: [alice]
+organizations:
+  cilium:
+    configFilename: cilium/team-assignments.yaml
+    overrideFilename: cilium/overrides.yaml
+  ebpf:
+    configFilename: /etc/team-manager/ebpf.yaml
+`,
+			want: map[string]config.OrganizationFiles{
+				"cilium": {
+					ConfigFilename:   "{dir}/cilium/team-assignments.yaml",
+					OverrideFilename: "{dir}/cilium/overrides.yaml",
+				},
+				"ebpf": {ConfigFilename: "/etc/team-manager/ebpf.yaml"},
+			},
+		},
+		{
+			name:    "unknown field",
+			orgs:    "organisations: {}\n",
+			wantErr: "failed to parse {file}",
+		},
+		{
+			name:    "invalid",
+			orgs:    "organizations:\n  cilium: {}\n",
+			wantErr: `invalid organizations file {file}: organization "cilium" does not have a configFilename`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "organizations.yaml")
+			if err := os.WriteFile(file, []byte(tt.orgs), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadOrganizations(file)
+			if tt.wantErr != "" {
+				wantErr := strings.ReplaceAll(tt.wantErr, "{file}", file)
+				if err == nil || !strings.HasPrefix(err.Error(), wantErr) {
+					t.Fatalf("LoadOrganizations() error = %v, want %q", err, wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadOrganizations() error = %v", err)
+			}
+			for name, files := range tt.want {
+				files.ConfigFilename = strings.ReplaceAll(files.ConfigFilename, "{dir}", dir)
+				files.OverrideFilename = strings.ReplaceAll(files.OverrideFilename, "{dir}", dir)
+				tt.want[name] = files
+			}
+			if !reflect.DeepEqual(got.Organizations, tt.want) {
+				t.Errorf("organizations = %+v, want %+v", got.Organizations, tt.want)
+			}
+			if _, ok := got.People["alice"]; !ok {
+				t.Errorf("people = %+v, want alice", got.People)
+			}
+		})
+	}
+}
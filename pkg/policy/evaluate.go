@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/access"
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Finding is a violation of a rule of the policy.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Severity, f.Message, f.Rule)
+}
+
+// Errors returns the number of findings with the error severity.
+func Errors(findings []Finding) int {
+	var errs int
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			errs++
+		}
+	}
+	return errs
+}
+
+// Evaluate evaluates all rules of the policy against the given configuration
+// and returns their findings, errors first.
+func Evaluate(p *Policy, cfg *config.Config) []Finding {
+	var findings []Finding
+	add := func(rule string, r Rule, format string, a ...interface{}) {
+		findings = append(findings, Finding{
+			Rule:     rule,
+			Severity: r.severity(),
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	for repoName, repo := range cfg.Repositories {
+		var maintainTeams int
+		for permission, usersOrTeams := range repo {
+			perm := permission.GetPermission()
+			if !permission.IsUser() {
+				if access.HasPermission(perm, "MAINTAIN") {
+					maintainTeams += len(usersOrTeams)
+				}
+				continue
+			}
+			for _, user := range usersOrTeams {
+				if p.NoDirectAdmin != nil && perm == "ADMIN" {
+					add("noDirectAdmin", *p.NoDirectAdmin,
+						"user %q has the ADMIN permission directly on repository %q", user, repoName)
+				}
+				if rule := p.MaxCollaboratorPermission; rule != nil &&
+					slices.Contains(rule.PrivateRepositories, repoName) &&
+					!access.HasPermission(rule.Permission, perm) {
+					if _, ok := cfg.Collaborators[string(user)]; ok {
+						add("maxCollaboratorPermission", rule.Rule,
+							"outside collaborator %q has the %s permission on private repository %q, at most %s is allowed", user, perm, repoName, rule.Permission)
+					}
+				}
+			}
+		}
+		if rule := p.MinMaintainTeams; rule != nil && maintainTeams < rule.Min {
+			add("minMaintainTeams", rule.Rule,
+				"repository %q has %d team(s) with the MAINTAIN permission or higher, at least %d required", repoName, maintainTeams, rule.Min)
+		}
+	}
+
+	now := time.Now()
+	for teamName, team := range cfg.AllTeams {
+		if rule := p.MinReviewTeamMembers; rule != nil && team.CodeReviewAssignment.Enabled {
+			if members := len(team.ActiveMembers(now)); members < rule.Min {
+				add("minReviewTeamMembers", rule.Rule,
+					"team %q has code review assignment enabled with %d member(s), at least %d required", teamName, members, rule.Min)
+			}
+		}
+		if rule := p.TeamNamePattern; rule != nil && !rule.re.MatchString(teamName) {
+			add("teamNamePattern", rule.Rule,
+				"team name %q does not match pattern %q", teamName, rule.Pattern)
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity == SeverityError
+		}
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+		return findings[i].Message < findings[j].Message
+	})
+	return findings
+}
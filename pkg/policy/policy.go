@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package policy evaluates organization policies, declared in a policy file,
+// against the configuration of the organization.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Severity is the severity of the findings of a rule.
+type Severity string
+
+const (
+	// SeverityError findings make 'lint' and 'push' fail.
+	SeverityError Severity = "error"
+	// SeverityWarning findings are only reported.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule contains the settings common to all rules.
+type Rule struct {
+	// Severity of the findings of this rule, 'error' if not set.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+func (r Rule) severity() Severity {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+func (r Rule) check() error {
+	switch r.Severity {
+	case "", SeverityError, SeverityWarning:
+		return nil
+	default:
+		return fmt.Errorf("invalid severity %q, valid severities are %q and %q", r.Severity, SeverityError, SeverityWarning)
+	}
+}
+
+// MinRule is a rule requiring a minimum number of something.
+type MinRule struct {
+	Rule `yaml:",inline"`
+	Min  int `json:"min" yaml:"min"`
+}
+
+// PermissionRule is a rule limiting the permission of outside collaborators
+// on repositories.
+type PermissionRule struct {
+	Rule       `yaml:",inline"`
+	Permission string `json:"permission" yaml:"permission"`
+
+	// PrivateRepositories are the repositories the rule applies to, since the
+	// visibility of the repositories is not part of the configuration.
+	PrivateRepositories []config.RepositoryName `json:"privateRepositories" yaml:"privateRepositories"`
+}
+
+// PatternRule is a rule requiring names to match a regular expression.
+type PatternRule struct {
+	Rule    `yaml:",inline"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// Policy is the set of rules of an organization. Rules that are not set are
+// not evaluated.
+type Policy struct {
+	// NoDirectAdmin forbids giving the ADMIN permission on a repository
+	// directly to a user instead of through a team.
+	NoDirectAdmin *Rule `json:"noDirectAdmin,omitempty" yaml:"noDirectAdmin,omitempty"`
+
+	// MinMaintainTeams requires every repository to have a minimum number of
+	// teams with the MAINTAIN permission or higher.
+	MinMaintainTeams *MinRule `json:"minMaintainTeams,omitempty" yaml:"minMaintainTeams,omitempty"`
+
+	// MinReviewTeamMembers requires every team with code review assignment
+	// enabled to have a minimum number of members.
+	MinReviewTeamMembers *MinRule `json:"minReviewTeamMembers,omitempty" yaml:"minReviewTeamMembers,omitempty"`
+
+	// MaxCollaboratorPermission limits the permission of outside
+	// collaborators on private repositories.
+	MaxCollaboratorPermission *PermissionRule `json:"maxCollaboratorPermission,omitempty" yaml:"maxCollaboratorPermission,omitempty"`
+
+	// TeamNamePattern requires all team names to match a regular expression.
+	TeamNamePattern *PatternRule `json:"teamNamePattern,omitempty" yaml:"teamNamePattern,omitempty"`
+}
+
+// Load reads and validates the policy file.
+func Load(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", file, err)
+	}
+	if err := p.check(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", file, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) check() error {
+	if p.NoDirectAdmin != nil {
+		if err := p.NoDirectAdmin.check(); err != nil {
+			return fmt.Errorf("rule noDirectAdmin: %w", err)
+		}
+	}
+	for _, r := range []struct {
+		name string
+		rule *MinRule
+	}{
+		{"minMaintainTeams", p.MinMaintainTeams},
+		{"minReviewTeamMembers", p.MinReviewTeamMembers},
+	} {
+		name, rule := r.name, r.rule
+		if rule == nil {
+			continue
+		}
+		if err := rule.check(); err != nil {
+			return fmt.Errorf("rule %s: %w", name, err)
+		}
+		if rule.Min < 1 {
+			return fmt.Errorf("rule %s: min must be at least 1", name)
+		}
+	}
+	if rule := p.MaxCollaboratorPermission; rule != nil {
+		if err := rule.check(); err != nil {
+			return fmt.Errorf("rule maxCollaboratorPermission: %w", err)
+		}
+		if config.GraphQLPerm2RestAPIPerm(rule.Permission) == "" {
+			return fmt.Errorf("rule maxCollaboratorPermission: invalid permission %q", rule.Permission)
+		}
+	}
+	if rule := p.TeamNamePattern; rule != nil {
+		if err := rule.check(); err != nil {
+			return fmt.Errorf("rule teamNamePattern: %w", err)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("rule teamNamePattern: %w", err)
+		}
+		rule.re = re
+	}
+	return nil
+}
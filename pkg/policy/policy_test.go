@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "all rules",
+			policy: `noDirectAdmin: {}
+minMaintainTeams:
+  min: 1
+minReviewTeamMembers:
+  severity: warning
+  min: 2
+maxCollaboratorPermission:
+  permission: WRITE
+  privateRepositories: [secret]
+teamNamePattern:
+  pattern: ^[a-z-]+$
+`,
+		},
+		{
+			name:   "no rules",
+			policy: "{}\n",
+		},
+		{
+			name:    "unknown rule",
+			policy:  "noDirectAdmins: {}\n",
+			wantErr: "failed to parse policy file",
+		},
+		{
+			name:    "invalid severity",
+			policy:  "noDirectAdmin:\n  severity: fatal\n",
+			wantErr: `rule noDirectAdmin: invalid severity "fatal"`,
+		},
+		{
+			name:    "min not set",
+			policy:  "minMaintainTeams: {}\n",
+			wantErr: "rule minMaintainTeams: min must be at least 1",
+		},
+		{
+			name:    "invalid permission",
+			policy:  "maxCollaboratorPermission:\n  permission: OWNER\n",
+			wantErr: `rule maxCollaboratorPermission: invalid permission "OWNER"`,
+		},
+		{
+			name:    "invalid pattern",
+			policy:  "teamNamePattern:\n  pattern: '[a-z'\n",
+			wantErr: "rule teamNamePattern: error parsing regexp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(file, []byte(tt.policy), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(file)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func policyConfig() *config.Config {
+	cfg := &config.Config{
+		Members: map[string]config.User{
+			"alice": {ID: "U1"},
+			"bob":   {ID: "U2"},
+			"carol": {ID: "U3"},
+		},
+		Collaborators: map[string]config.OutsideCollaborator{
+			"eve": {},
+		},
+		Teams: map[string]*config.TeamConfig{
+			"maintainers": {
+				ID:      "T1",
+				Members: []string{"alice", "bob"},
+				CodeReviewAssignment: config.CodeReviewAssignment{
+					Enabled: true,
+				},
+			},
+			"Reviewers": {
+				ID:      "T2",
+				Members: []string{"carol"},
+			},
+		},
+		Repositories: map[config.RepositoryName]config.Repository{
+			"cilium": {
+				"MAINTAIN":   {"maintainers"},
+				"USER-WRITE": {"eve"},
+			},
+			"secret": {
+				"ADMIN":      {"maintainers"},
+				"USER-ADMIN": {"alice", "eve"},
+			},
+		},
+	}
+	cfg.IndexTeams()
+	return cfg
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		// change changes the configuration before evaluating the policy.
+		change func(cfg *config.Config)
+		want   []Finding
+	}{
+		{
+			name: "no rules",
+		},
+		{
+			name:   "noDirectAdmin",
+			policy: Policy{NoDirectAdmin: &Rule{}},
+			want: []Finding{
+				{Rule: "noDirectAdmin", Severity: SeverityError, Message: `user "alice" has the ADMIN permission directly on repository "secret"`},
+				{Rule: "noDirectAdmin", Severity: SeverityError, Message: `user "eve" has the ADMIN permission directly on repository "secret"`},
+			},
+		},
+		{
+			name:   "minMaintainTeams",
+			policy: Policy{MinMaintainTeams: &MinRule{Min: 1}},
+			change: func(cfg *config.Config) {
+				cfg.Repositories["cilium"]["WRITE"] = cfg.Repositories["cilium"]["MAINTAIN"]
+				delete(cfg.Repositories["cilium"], "MAINTAIN")
+			},
+			want: []Finding{
+				{Rule: "minMaintainTeams", Severity: SeverityError, Message: `repository "cilium" has 0 team(s) with the MAINTAIN permission or higher, at least 1 required`},
+			},
+		},
+		{
+			name:   "minMaintainTeams ignores users",
+			policy: Policy{MinMaintainTeams: &MinRule{Min: 2}},
+			want: []Finding{
+				{Rule: "minMaintainTeams", Severity: SeverityError, Message: `repository "cilium" has 1 team(s) with the MAINTAIN permission or higher, at least 2 required`},
+				{Rule: "minMaintainTeams", Severity: SeverityError, Message: `repository "secret" has 1 team(s) with the MAINTAIN permission or higher, at least 2 required`},
+			},
+		},
+		{
+			name:   "minReviewTeamMembers",
+			policy: Policy{MinReviewTeamMembers: &MinRule{Rule: Rule{Severity: SeverityWarning}, Min: 3}},
+			want: []Finding{
+				{Rule: "minReviewTeamMembers", Severity: SeverityWarning, Message: `team "maintainers" has code review assignment enabled with 2 member(s), at least 3 required`},
+			},
+		},
+		{
+			name:   "minReviewTeamMembers ignores expired members",
+			policy: Policy{MinReviewTeamMembers: &MinRule{Min: 2}},
+			change: func(cfg *config.Config) {
+				cfg.Teams["maintainers"].MembersUntil = map[string]config.Date{
+					"bob": config.NewDate(time.Now().AddDate(0, 0, -2)),
+				}
+			},
+			want: []Finding{
+				{Rule: "minReviewTeamMembers", Severity: SeverityError, Message: `team "maintainers" has code review assignment enabled with 1 member(s), at least 2 required`},
+			},
+		},
+		{
+			name: "maxCollaboratorPermission",
+			policy: Policy{MaxCollaboratorPermission: &PermissionRule{
+				Permission:          "WRITE",
+				PrivateRepositories: []config.RepositoryName{"cilium", "secret"},
+			}},
+			want: []Finding{
+				{Rule: "maxCollaboratorPermission", Severity: SeverityError, Message: `outside collaborator "eve" has the ADMIN permission on private repository "secret", at most WRITE is allowed`},
+			},
+		},
+		{
+			name: "maxCollaboratorPermission on public repositories",
+			policy: Policy{MaxCollaboratorPermission: &PermissionRule{
+				Permission:          "READ",
+				PrivateRepositories: []config.RepositoryName{"other"},
+			}},
+		},
+		{
+			name:   "teamNamePattern",
+			policy: Policy{TeamNamePattern: &PatternRule{Pattern: "^[a-z-]+$"}},
+			want: []Finding{
+				{Rule: "teamNamePattern", Severity: SeverityError, Message: `team name "Reviewers" does not match pattern "^[a-z-]+$"`},
+			},
+		},
+		{
+			name: "errors first",
+			policy: Policy{
+				NoDirectAdmin:   &Rule{Severity: SeverityWarning},
+				TeamNamePattern: &PatternRule{Pattern: "^[a-z-]+$"},
+			},
+			want: []Finding{
+				{Rule: "teamNamePattern", Severity: SeverityError, Message: `team name "Reviewers" does not match pattern "^[a-z-]+$"`},
+				{Rule: "noDirectAdmin", Severity: SeverityWarning, Message: `user "alice" has the ADMIN permission directly on repository "secret"`},
+				{Rule: "noDirectAdmin", Severity: SeverityWarning, Message: `user "eve" has the ADMIN permission directly on repository "secret"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.check(); err != nil {
+				t.Fatalf("invalid policy: %v", err)
+			}
+			cfg := policyConfig()
+			if tt.change != nil {
+				tt.change(cfg)
+			}
+
+			got := Evaluate(&tt.policy, cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gh "github.com/google/go-github/v79/github"
+)
+
+// PermissionError is a failure to grant or revoke the permission of a team or
+// user in a repository.
+type PermissionError struct {
+	Repository string
+	// Principal is the name of the team or the login of the user.
+	Principal string
+	// IsTeam is true if Principal is a team.
+	IsTeam bool
+	// Permission is the permission that failed to be granted. Empty if the
+	// permission failed to be revoked.
+	Permission string
+	// StatusCode is the HTTP status code returned by GitHub, 0 if GitHub
+	// didn't reply.
+	StatusCode int
+
+	Err error
+}
+
+func newPermissionError(repo, principal string, isTeam bool, perm string, resp *gh.Response, err error) *PermissionError {
+	e := &PermissionError{
+		Repository: repo,
+		Principal:  principal,
+		IsTeam:     isTeam,
+		Permission: perm,
+		Err:        err,
+	}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+	}
+	return e
+}
+
+func (e *PermissionError) Error() string {
+	principal := fmt.Sprintf("user %q", e.Principal)
+	if e.IsTeam {
+		principal = fmt.Sprintf("team %q", e.Principal)
+	}
+	action := "revoke permissions of"
+	if e.Permission != "" {
+		action = fmt.Sprintf("grant permission %q to", e.Permission)
+	}
+	status := "no response"
+	if e.StatusCode != 0 {
+		status = fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unable to %s %s in repository %q (%s): %s", action, principal, e.Repository, status, e.Err)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionErrors aggregates all failures to change repository permissions.
+type PermissionErrors []*PermissionError
+
+func (e PermissionErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d repository permission change(s) failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// collectPermissionErrors returns errs with the permission errors of err
+// appended to it, if the manager continues on errors. Otherwise, or if err
+// isn't caused by permission errors, err is returned.
+func (tm *Manager) collectPermissionErrors(errs PermissionErrors, err error) (PermissionErrors, error) {
+	var permErrs PermissionErrors
+	if !tm.ContinueOnError || !errors.As(err, &permErrs) {
+		return errs, err
+	}
+	return append(errs, permErrs...), nil
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v79/github"
+)
+
+// fakeGitHub returns a manager talking to a fake GitHub REST API, which
+// rejects the requests about "ghost" teams and users, and the requests it
+// received with their body, if any.
+func fakeGitHub(t *testing.T) (*Manager, *[]string) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		if body, _ := io.ReadAll(r.Body); len(body) != 0 {
+			request += " " + strings.TrimSpace(string(body))
+		}
+		requests = append(requests, request)
+		if strings.Contains(r.URL.Path, "ghost") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	ghClient := gh.NewClient(srv.Client())
+	baseURL, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ghClient.BaseURL = baseURL
+	return &Manager{owner: "cilium", ghClient: ghClient}, &requests
+}
+
+func TestPushRepositoryPermissions(t *testing.T) {
+	tests := []struct {
+		name            string
+		users           bool
+		add, remove     []string
+		continueOnError bool
+		wantRequests    []string
+		wantErrs        []PermissionError
+	}{
+		{
+			name:   "teams",
+			add:    []string{"sig-foo"},
+			remove: []string{"sig-bar"},
+			wantRequests: []string{
+				"DELETE /orgs/cilium/teams/sig-bar/repos/cilium/cilium",
+				`PUT /orgs/cilium/teams/sig-foo/repos/cilium/cilium {"permission":"push"}`,
+			},
+		},
+		{
+			name:   "failed team stops the push",
+			add:    []string{"ghost", "sig-foo"},
+			remove: []string{"sig-bar"},
+			wantRequests: []string{
+				"DELETE /orgs/cilium/teams/sig-bar/repos/cilium/cilium",
+				`PUT /orgs/cilium/teams/ghost/repos/cilium/cilium {"permission":"push"}`,
+			},
+			wantErrs: []PermissionError{
+				{Repository: "cilium", Principal: "ghost", IsTeam: true, Permission: "WRITE", StatusCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:            "failed teams with continue on error",
+			add:             []string{"ghost", "sig-foo"},
+			remove:          []string{"ghost"},
+			continueOnError: true,
+			wantRequests: []string{
+				"DELETE /orgs/cilium/teams/ghost/repos/cilium/cilium",
+				`PUT /orgs/cilium/teams/ghost/repos/cilium/cilium {"permission":"push"}`,
+				`PUT /orgs/cilium/teams/sig-foo/repos/cilium/cilium {"permission":"push"}`,
+			},
+			wantErrs: []PermissionError{
+				{Repository: "cilium", Principal: "ghost", IsTeam: true, StatusCode: http.StatusNotFound},
+				{Repository: "cilium", Principal: "ghost", IsTeam: true, Permission: "WRITE", StatusCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:   "failed user stops the push",
+			users:  true,
+			add:    []string{"aanm"},
+			remove: []string{"ghost", "joestringer"},
+			wantRequests: []string{
+				"DELETE /repos/cilium/cilium/collaborators/ghost",
+			},
+			wantErrs: []PermissionError{
+				{Repository: "cilium", Principal: "ghost", StatusCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:            "failed users with continue on error",
+			users:           true,
+			add:             []string{"ghost", "aanm"},
+			remove:          []string{"joestringer"},
+			continueOnError: true,
+			wantRequests: []string{
+				"DELETE /repos/cilium/cilium/collaborators/joestringer",
+				`PUT /repos/cilium/cilium/collaborators/ghost {"permission":"push"}`,
+				`PUT /repos/cilium/cilium/collaborators/aanm {"permission":"push"}`,
+			},
+			wantErrs: []PermissionError{
+				{Repository: "cilium", Principal: "ghost", Permission: "WRITE", StatusCode: http.StatusNotFound},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, requests := fakeGitHub(t)
+			tm.ContinueOnError = tt.continueOnError
+
+			var err error
+			if tt.users {
+				err = tm.PushRepositoryMembersPermissions(context.Background(), "cilium", "WRITE", tt.add, tt.remove)
+			} else {
+				err = tm.PushRepositoryTeamPermissions(context.Background(), "cilium", "WRITE", tt.add, tt.remove)
+			}
+
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", *requests, tt.wantRequests)
+			}
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var permErrs PermissionErrors
+			if !errors.As(err, &permErrs) {
+				t.Fatalf("error = %v, want PermissionErrors", err)
+			}
+			var got []PermissionError
+			for _, permErr := range permErrs {
+				if permErr.Err == nil {
+					t.Errorf("%s: cause not set", permErr)
+				}
+				e := *permErr
+				e.Err = nil
+				got = append(got, e)
+			}
+			if !reflect.DeepEqual(got, tt.wantErrs) {
+				t.Errorf("errors = %+v, want %+v", got, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestPermissionError(t *testing.T) {
+	cause := errors.New("boom")
+	tests := []struct {
+		err  *PermissionError
+		want string
+	}{
+		{
+			err:  &PermissionError{Repository: "cilium", Principal: "sig-foo", IsTeam: true, Permission: "WRITE", StatusCode: http.StatusForbidden, Err: cause},
+			want: `unable to grant permission "WRITE" to team "sig-foo" in repository "cilium" (HTTP 403): boom`,
+		},
+		{
+			err:  &PermissionError{Repository: "cilium", Principal: "aanm", Err: cause},
+			want: `unable to revoke permissions of user "aanm" in repository "cilium" (no response): boom`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+		if !errors.Is(tt.err, cause) {
+			t.Errorf("errors.Is(%v, cause) = false", tt.err)
+		}
+	}
+
+	errs := PermissionErrors{tests[0].err, tests[1].err}
+	if want := "2 repository permission change(s) failed: " + tests[0].want + "; " + tests[1].want; errs.Error() != want {
+		t.Errorf("Error() = %q, want %q", errs.Error(), want)
+	}
+}
+
+func TestCollectPermissionErrors(t *testing.T) {
+	permErr := &PermissionError{Repository: "cilium", Principal: "ghost"}
+	otherErr := errors.New("boom")
+	tests := []struct {
+		name            string
+		continueOnError bool
+		err             error
+		wantErrs        PermissionErrors
+		wantErr         error
+	}{
+		{
+			name:     "stop on error",
+			err:      PermissionErrors{permErr},
+			wantErrs: PermissionErrors{permErr},
+			wantErr:  PermissionErrors{permErr},
+		},
+		{
+			name:            "continue on error",
+			continueOnError: true,
+			err:             PermissionErrors{permErr},
+			wantErrs:        PermissionErrors{permErr, permErr},
+		},
+		{
+			name:            "other error",
+			continueOnError: true,
+			err:             otherErr,
+			wantErrs:        PermissionErrors{permErr},
+			wantErr:         otherErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &Manager{ContinueOnError: tt.continueOnError}
+			errs, err := tm.collectPermissionErrors(PermissionErrors{permErr}, tt.err)
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("collectPermissionErrors() errors = %v, want %v", errs, tt.wantErrs)
+			}
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("collectPermissionErrors() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	gh "github.com/google/go-github/v79/github"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// ListInvitations returns all pending and failed invitations to join the
+// organization, sorted by creation date.
+func (tm *Manager) ListInvitations(ctx context.Context) ([]config.Invitation, error) {
+	pending, err := tm.listOrgInvitations(ctx, tm.ghClient.Organizations.ListPendingOrgInvitations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the list of pending org invitations: %w", err)
+	}
+	failed, err := tm.listOrgInvitations(ctx, tm.ghClient.Organizations.ListFailedOrgInvitations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the list of failed org invitations: %w", err)
+	}
+
+	var invitations []config.Invitation
+	for _, invitation := range append(pending, failed...) {
+		createdAt := invitation.GetCreatedAt().Time
+		invitations = append(invitations, config.Invitation{
+			ID:           invitation.GetID(),
+			Login:        invitation.GetLogin(),
+			Email:        invitation.GetEmail(),
+			Role:         invitation.GetRole(),
+			Inviter:      invitation.GetInviter().GetLogin(),
+			CreatedAt:    createdAt,
+			ExpiresAt:    createdAt.Add(config.InvitationExpiry),
+			FailedAt:     invitation.GetFailedAt().Time,
+			FailedReason: invitation.GetFailedReason(),
+		})
+	}
+	sort.SliceStable(invitations, func(i, j int) bool {
+		return invitations[i].CreatedAt.Before(invitations[j].CreatedAt)
+	})
+	return invitations, nil
+}
+
+func (tm *Manager) listOrgInvitations(ctx context.Context, list func(context.Context, string, *gh.ListOptions) ([]*gh.Invitation, *gh.Response, error)) ([]*gh.Invitation, error) {
+	var allInvitations []*gh.Invitation
+	page := 0
+	for {
+		invitations, resp, err := list(ctx, tm.owner, &gh.ListOptions{
+			Page: page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		allInvitations = append(allInvitations, invitations...)
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return allInvitations, nil
+}
+
+// CancelInvitation cancels a pending invitation to join the organization.
+func (tm *Manager) CancelInvitation(ctx context.Context, invitation config.Invitation) error {
+	fmt.Printf("Cancelling invitation of %s\n", invitation.Invitee())
+	_, err := tm.ghClient.Organizations.CancelInvite(ctx, tm.owner, invitation.ID)
+	if err != nil {
+		return fmt.Errorf("unable to cancel invitation of %q: %w", invitation.Invitee(), err)
+	}
+	return nil
+}
+
+// ResendInvitation sends again an invitation to join the organization, with
+// the same role and teams. If the invitation is still pending, it is cancelled
+// first.
+func (tm *Manager) ResendInvitation(ctx context.Context, invitation config.Invitation) error {
+	if invitation.Failed() && invitation.Login != "" {
+		// Failed invitations are kept by GitHub even if the user joined the
+		// organization later on.
+		isMember, _, err := tm.ghClient.Organizations.IsMember(ctx, tm.owner, invitation.Login)
+		if err != nil {
+			return fmt.Errorf("unable to check if %q is a member of the organization: %w", invitation.Login, err)
+		}
+		if isMember {
+			fmt.Printf("Not re-sending invitation of %s, already a member of the organization\n", invitation.Login)
+			return nil
+		}
+	}
+
+	var teamIDs []int64
+	if !invitation.Failed() {
+		teams, _, err := tm.ghClient.Organizations.ListOrgInvitationTeams(ctx, tm.owner, fmt.Sprintf("%d", invitation.ID), nil)
+		if err != nil {
+			return fmt.Errorf("unable to get the teams of the invitation of %q: %w", invitation.Invitee(), err)
+		}
+		for _, t := range teams {
+			teamIDs = append(teamIDs, t.GetID())
+		}
+		if err := tm.CancelInvitation(ctx, invitation); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Re-sending invitation of %s\n", invitation.Invitee())
+	opts := &gh.CreateOrgInvitationOptions{
+		TeamID: teamIDs,
+	}
+	if invitation.Role != "" {
+		opts.Role = gh.Ptr(invitation.Role)
+	}
+	if invitation.Login != "" {
+		user, _, err := tm.ghClient.Users.Get(ctx, invitation.Login)
+		if err != nil {
+			return fmt.Errorf("unable to fetch information about user %q: %w", invitation.Login, err)
+		}
+		opts.InviteeID = user.ID
+	} else {
+		opts.Email = gh.Ptr(invitation.Email)
+	}
+	_, _, err := tm.ghClient.Organizations.CreateOrgInvitation(ctx, tm.owner, opts)
+	if err != nil {
+		return fmt.Errorf("unable to invite %q to the organization: %w", invitation.Invitee(), err)
+	}
+	return nil
+}
+
+// invitedMembers returns a description of the members of the local
+// configuration that were invited into the organization but didn't accept the
+// invitation yet.
+func invitedMembers(localCfg, upstreamCfg *config.Config) []string {
+	var invited []string
+	for login := range localCfg.Members {
+		if _, ok := upstreamCfg.Members[login]; ok {
+			continue
+		}
+		if invitation, ok := upstreamCfg.PendingInvitation(login); ok {
+			invited = append(invited, fmt.Sprintf("%s: invited, not yet member (%s)", login, invitation))
+		}
+	}
+	sort.Strings(invited)
+	return invited
+}
+
+// checkInvitationStatus prints the members of the local configuration that
+// didn't accept their invitation to join the organization yet, or whose
+// invitation failed or expired.
+func (tm *Manager) checkInvitationStatus(ctx context.Context, localCfg *config.Config) error {
+	invitations, err := tm.ListInvitations(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, invitation := range invitations {
+		if _, ok := localCfg.Members[invitation.Login]; !ok {
+			continue
+		}
+		if !invitation.Failed() {
+			fmt.Printf("Member %q is invited, not yet member (%s)\n", invitation.Login, invitation)
+			continue
+		}
+		// Failed invitations are kept by GitHub even if the user joined the
+		// organization later on.
+		isMember, _, err := tm.ghClient.Organizations.IsMember(ctx, tm.owner, invitation.Login)
+		if err != nil {
+			return fmt.Errorf("unable to check if %q is a member of the organization: %w", invitation.Login, err)
+		}
+		if !isMember {
+			fmt.Printf("Member %q did not join the organization (%s, %s ago)\n", invitation.Login, invitation, invitation.Age(now).Round(time.Hour))
+		}
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/cilium/team-manager/pkg/comparator"
 	config "github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/journal"
 	"github.com/cilium/team-manager/pkg/slices"
 )
 
@@ -40,6 +41,26 @@ type Manager struct {
 
 	// AuthenticatedUser is the user authenticated with GH.
 	AuthenticatedUser string
+
+	// ContinueOnError makes a push apply all repository permissions it can,
+	// returning all failures at the end, instead of stopping on the first
+	// one.
+	ContinueOnError bool
+
+	// journal, if set, records every operation applied to GitHub.
+	journal *journal.Journal
+	// persist, if set, stores the local configuration after every operation
+	// applied to GitHub.
+	persist func() error
+	// upstreamCfg is the configuration fetched from GitHub at the beginning
+	// of a push. It is used to record the previous state of every operation
+	// in the journal.
+	upstreamCfg *config.Config
+	// slugs maps the name of the teams to their GitHub slug.
+	slugs map[string]string
+	// applied are the operations already applied by the interrupted push
+	// being resumed, without their previous state.
+	applied map[journal.Operation]struct{}
 }
 
 func NewManager(ghClient *gh.Client, gqlGHClient *githubv4.Client, owner string) (*Manager, error) {
@@ -69,6 +90,54 @@ func NewManager(ghClient *gh.Client, gqlGHClient *githubv4.Client, owner string)
 	return nil, fmt.Errorf("failed to authenticate with GHApp and user. User error: %v", err)
 }
 
+// SetJournal records every operation applied to GitHub in the given journal
+// and calls persist after each one of them, so that the local configuration
+// is never behind GitHub if a push stops midway.
+func (tm *Manager) SetJournal(j *journal.Journal, persist func() error) {
+	tm.journal = j
+	tm.persist = persist
+}
+
+// SkipApplied makes the next push skip the given operations, applied by an
+// interrupted push that is being resumed.
+func (tm *Manager) SkipApplied(ops []journal.Operation) {
+	tm.applied = make(map[journal.Operation]struct{}, len(ops))
+	for _, op := range ops {
+		op.Previous = nil
+		tm.applied[op] = struct{}{}
+	}
+}
+
+// alreadyApplied returns true if the given operation was applied by the
+// interrupted push being resumed, and should be skipped. Creating and renaming
+// teams are never skipped since the rest of the push depends on their result.
+func (tm *Manager) alreadyApplied(op journal.Operation) bool {
+	op.Previous = nil
+	if _, ok := tm.applied[op]; !ok {
+		return false
+	}
+	fmt.Printf("Skipping %s, applied before the push was interrupted\n", op)
+	return true
+}
+
+// record stores the given operation, applied to GitHub, in the journal and
+// persists the local configuration.
+func (tm *Manager) record(op journal.Operation) error {
+	if tm.journal == nil {
+		return nil
+	}
+	if err := tm.journal.Record(op); err != nil {
+		return fmt.Errorf("unable to record operation %q in journal: %w", op, err)
+	}
+	if tm.persist == nil {
+		return nil
+	}
+	if err := tm.persist(); err != nil {
+		return fmt.Errorf("unable to persist local configuration after operation %q: %w", op, err)
+	}
+	return nil
+}
+
 // PullConfiguration returns a *config.Config by querying the organization teams.
 // It will not populate the excludedMembers from CodeReviewAssignments as GH
 // does not provide an API of such field.
@@ -99,12 +168,22 @@ func (tm *Manager) PullConfiguration(ctx context.Context) (*config.Config, error
 		return nil, err
 	}
 
+	// Get all invitations not accepted yet
+	c.Invitations, err = tm.ListInvitations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	err = config.SanityCheck(c)
 	if err != nil {
 		return nil, err
 	}
 
-	config.SortConfig(c)
+	if err := config.SortConfig(c); err != nil {
+		return nil, err
+	}
+
+	tm.UseTeamSlugs(c)
 
 	return c, nil
 }
@@ -203,11 +282,12 @@ func (tm *Manager) fetchMembers(ctx context.Context, c *config.Config) error {
 			}
 			requeryMembers = false
 		}
-		for _, member := range resultMembers.Organization.Members.Nodes {
-			strLogin := string(member.Login)
+		for _, member := range resultMembers.Organization.Members.Edges {
+			strLogin := string(member.Node.Login)
 			c.Members[strLogin] = config.User{
-				ID:   fmt.Sprintf("%v", member.ID),
-				Name: string(member.Name),
+				ID:   fmt.Sprintf("%v", member.Node.ID),
+				Name: string(member.Node.Name),
+				Role: config.ParseOrgRoleFromGraphQL(member.Role),
 			}
 			bar.Add(1)
 		}
@@ -322,6 +402,7 @@ func (tm *Manager) fetchTeams(ctx context.Context, c *config.Config) error {
 				teamCfg = &config.TeamConfig{
 					ID:                   fmt.Sprintf("%v", t.ID),
 					RESTID:               t.DatabaseId,
+					Slug:                 string(t.Slug),
 					Description:          string(t.Description),
 					ParentTeam:           config.TeamOrMemberName(t.ParentTeam.Name),
 					Privacy:              config.TeamPrivacy(t.Privacy),
@@ -346,11 +427,15 @@ func (tm *Manager) fetchTeams(ctx context.Context, c *config.Config) error {
 						return err
 					}
 				}
-				for _, member := range t.Members.Nodes {
-					strLogin := string(member.Login)
+				for _, member := range t.Members.Edges {
+					strLogin := string(member.Node.Login)
 					teamCfg.Members = append(teamCfg.Members, strLogin)
+					if member.Role == githubv4.TeamMemberRoleMaintainer {
+						teamCfg.Maintainers = append(teamCfg.Maintainers, strLogin)
+					}
 				}
 				sort.Strings(teamCfg.Members)
+				sort.Strings(teamCfg.Maintainers)
 				c.Teams[strTeamName] = teamCfg
 				if !t.Members.PageInfo.HasNextPage {
 					break
@@ -377,13 +462,30 @@ func (tm *Manager) Diff(ctx context.Context, localCfg *config.Config, opts confi
 	if err != nil {
 		return "", fmt.Errorf("unable to get upstream config: %w", err)
 	}
+	invited := invitedMembers(localCfg, upstreamCfg)
+	renamed := renamedMembers(localCfg, upstreamCfg)
 	upstreamCfg.Normalize(opts)
 
+	// The role of members without a role in the local config is not managed
+	// by team-manager.
+	for login, upstreamMember := range upstreamCfg.Members {
+		if localMember, ok := localCfg.Members[login]; ok && localMember.Role == "" {
+			upstreamMember.Role = ""
+			upstreamCfg.Members[login] = upstreamMember
+		}
+	}
+
 	if localCfg.Equals(upstreamCfg) {
 		return "", nil
 	}
 
 	cmp := comparator.CompareWithNames(localCfg, upstreamCfg, "local", "remote")
+	if len(invited) != 0 {
+		cmp += fmt.Sprintf("\nMembers with a pending invitation:\n  %s\n", strings.Join(invited, "\n  "))
+	}
+	if len(renamed) != 0 {
+		cmp += fmt.Sprintf("\nMembers renamed in GitHub:\n  %s\n", strings.Join(renamed, "\n  "))
+	}
 	return cmp, nil
 }
 
@@ -393,6 +495,14 @@ func (tm *Manager) PushConfiguration(ctx context.Context, localCfg *config.Confi
 	if err != nil {
 		return nil, fmt.Errorf("unable to get upstream config: %w", err)
 	}
+	tm.upstreamCfg = upstreamCfg
+
+	// Pushing members renamed in GitHub would remove them from the
+	// organization and invite them again under their new login.
+	if renames := renamedMembers(localCfg, upstreamCfg); len(renames) != 0 {
+		fmt.Printf("The following members were renamed in GitHub:\n  %s\n", strings.Join(renames, "\n  "))
+		return nil, fmt.Errorf("%d member(s) renamed in GitHub, migrate their logins before pushing", len(renames))
+	}
 
 	if pushRepos {
 		// Check repository sync
@@ -402,6 +512,16 @@ func (tm *Manager) PushConfiguration(ctx context.Context, localCfg *config.Confi
 		}
 	}
 
+	// Check that no protected member, team or repository is affected before
+	// any change is submitted.
+	if violations := checkProtections(localCfg, upstreamCfg, pushRepos, pushMembers, pushTeams); len(violations) != 0 {
+		fmt.Printf("The following changes are not allowed:\n")
+		for _, violation := range violations {
+			fmt.Printf("  %s\n", violation)
+		}
+		return nil, fmt.Errorf("%d change(s) blocked by protections", len(violations))
+	}
+
 	if pushMembers {
 		// Sync Members
 		err = tm.pushMembers(ctx, force, dryRun, localCfg, upstreamCfg)
@@ -499,6 +619,10 @@ func (tm *Manager) CheckUserStatus(ctx context.Context, localCfg *config.Config)
 
 	fmt.Printf("Found %d teams with %d unique members\n", len(localCfg.AllTeams), len(localCfg.Members))
 
+	if err := tm.checkInvitationStatus(ctx, localCfg); err != nil {
+		return err
+	}
+
 	for member := range localCfg.Members {
 		fmt.Printf("Checking status of %q\n", member)
 		status, err := tm.fetchMemberLimitedAvailability(ctx, member)
@@ -521,7 +645,7 @@ func (tm *Manager) CheckUserStatus(ctx context.Context, localCfg *config.Config)
 		}
 
 		excludedTeamMembers := map[string]struct{}{}
-		for _, xMember := range team.CodeReviewAssignment.ExcludedMembers {
+		for _, xMember := range config.ActiveExcludedMembers(team.CodeReviewAssignment.ExcludedMembers, time.Now()) {
 			excludedTeamMembers[xMember.Login] = struct{}{}
 		}
 
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/journal"
+)
+
+func TestAlreadyApplied(t *testing.T) {
+	tm := &Manager{}
+	op := journal.Operation{Kind: journal.KindRemoveTeamMember, Team: "sig-foo", Member: "aanm"}
+	if tm.alreadyApplied(op) {
+		t.Errorf("alreadyApplied() without a resumed push = true")
+	}
+
+	applied := op
+	applied.Previous = &journal.State{Role: "maintainer"}
+	tm.SkipApplied([]journal.Operation{applied})
+
+	tests := []struct {
+		op   journal.Operation
+		want bool
+	}{
+		// The previous state is only known once applied.
+		{op, true},
+		{journal.Operation{Kind: journal.KindAddTeamMember, Team: "sig-foo", Member: "aanm"}, false},
+		{journal.Operation{Kind: journal.KindRemoveTeamMember, Team: "sig-bar", Member: "aanm"}, false},
+	}
+	for _, tt := range tests {
+		if got := tm.alreadyApplied(tt.op); got != tt.want {
+			t.Errorf("alreadyApplied(%s) = %v, want %v", tt.op, got, tt.want)
+		}
+	}
+}
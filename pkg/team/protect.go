@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// protectionViolation is a change that would be pushed into GitHub but is
+// blocked by one of the protections from the local configuration.
+type protectionViolation struct {
+	// change describes the change that was blocked.
+	change string
+	// protection describes the protection that blocked the change.
+	protection string
+}
+
+func (v protectionViolation) String() string {
+	return fmt.Sprintf("%s: blocked by %s", v.change, v.protection)
+}
+
+// checkProtections returns all the changes, between the local and upstream
+// configuration, that would remove, delete or demote anything protected by
+// localCfg.Protected.
+func checkProtections(localCfg, upstreamCfg *config.Config, pushRepos, pushMembers, pushTeams bool) []protectionViolation {
+	var violations []protectionViolation
+	protected := localCfg.Protected
+	// Renamed teams are neither deleted nor lose any permission. Conflicting
+	// renames are reported by pushTeams.
+	renames, _ := teamRenames(localCfg, upstreamCfg)
+	// protectedTeam returns the name under which the given upstream team is
+	// protected, if any. Teams renamed by the push are protected by either
+	// of their names.
+	protectedTeam := func(upstreamName string) (string, bool) {
+		if protected.IsTeam(upstreamName) {
+			return upstreamName, true
+		}
+		if newName, ok := renames[upstreamName]; ok && protected.IsTeam(newName) {
+			return newName, true
+		}
+		return "", false
+	}
+
+	if pushMembers {
+		for member, upstreamMember := range upstreamCfg.Members {
+			if !protected.IsMember(member) {
+				continue
+			}
+			localMember, ok := localCfg.Members[member]
+			if !ok {
+				violations = append(violations, protectionViolation{
+					change:     fmt.Sprintf("remove member %q from the organization", member),
+					protection: fmt.Sprintf("protected member %q", member),
+				})
+				continue
+			}
+			if upstreamMember.Role == config.OrgRoleAdmin && localMember.Role == config.OrgRoleMember {
+				violations = append(violations, protectionViolation{
+					change:     fmt.Sprintf("demote owner %q to a regular member of the organization", member),
+					protection: fmt.Sprintf("protected member %q", member),
+				})
+			}
+		}
+	}
+
+	if pushTeams {
+		for teamName, upstreamTeam := range upstreamCfg.AllTeams {
+			if _, ok := localCfg.AllTeams[teamName]; ok {
+				continue
+			}
+			if _, ok := renames[teamName]; ok {
+				continue
+			}
+			if protected.IsTeam(teamName) {
+				violations = append(violations, protectionViolation{
+					change:     fmt.Sprintf("delete team %q", teamName),
+					protection: fmt.Sprintf("protected team %q", teamName),
+				})
+			}
+			// GitHub deletes all children of a team as well.
+			for _, descendent := range upstreamTeam.Descendents() {
+				if name, ok := protectedTeam(descendent); ok {
+					violations = append(violations, protectionViolation{
+						change:     fmt.Sprintf("delete team %q, parent of %q", teamName, descendent),
+						protection: fmt.Sprintf("protected team %q", name),
+					})
+				}
+			}
+		}
+	}
+
+	if pushRepos {
+		for repoName, upstreamRepo := range upstreamCfg.Repositories {
+			localUsers, localTeams := repoPermissions(localCfg.Repositories[repoName])
+			upstreamUsers, upstreamTeams := repoPermissions(upstreamRepo)
+
+			for user, perm := range upstreamUsers {
+				if perm.GetPermission() != "ADMIN" || localUsers[user].GetPermission() == "ADMIN" {
+					continue
+				}
+				change := fmt.Sprintf("remove admin permission of member %q from repository %q", user, repoName)
+				if protected.IsMember(string(user)) {
+					violations = append(violations, protectionViolation{
+						change:     change,
+						protection: fmt.Sprintf("protected member %q", user),
+					})
+				}
+				if protected.IsRepository(repoName) {
+					violations = append(violations, protectionViolation{
+						change:     change,
+						protection: fmt.Sprintf("protected repository %q", repoName),
+					})
+				}
+			}
+			for team, perm := range upstreamTeams {
+				localTeam := team
+				if newName, ok := renames[string(team)]; ok {
+					localTeam = config.TeamOrMemberName(newName)
+				}
+				if perm.GetPermission() != "ADMIN" || localTeams[localTeam].GetPermission() == "ADMIN" {
+					continue
+				}
+				change := fmt.Sprintf("remove admin permission of team %q from repository %q", team, repoName)
+				if name, ok := protectedTeam(string(team)); ok {
+					violations = append(violations, protectionViolation{
+						change:     change,
+						protection: fmt.Sprintf("protected team %q", name),
+					})
+				}
+				if protected.IsRepository(repoName) {
+					violations = append(violations, protectionViolation{
+						change:     change,
+						protection: fmt.Sprintf("protected repository %q", repoName),
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].String() < violations[j].String()
+	})
+	return violations
+}
+
+// repoPermissions returns the permission of each user and team of the given
+// repository.
+func repoPermissions(repo config.Repository) (users, teams map[config.TeamOrMemberName]config.Permission) {
+	users = map[config.TeamOrMemberName]config.Permission{}
+	teams = map[config.TeamOrMemberName]config.Permission{}
+	for perm, usersOrTeams := range repo {
+		if perm.IsUser() {
+			for _, user := range usersOrTeams {
+				users[user] = perm
+			}
+		} else {
+			for _, team := range usersOrTeams {
+				teams[team] = perm
+			}
+		}
+	}
+	return users, teams
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func protectionsConfig() *config.Config {
+	cfg := &config.Config{
+		Members: map[string]config.User{
+			"owner": {ID: "U1", Role: config.OrgRoleAdmin},
+			"alice": {ID: "U2", Role: config.OrgRoleMember},
+			"bob":   {ID: "U3", Role: config.OrgRoleMember},
+		},
+		Teams: map[string]*config.TeamConfig{
+			"parent": {
+				ID: "T1",
+				Children: map[string]*config.TeamConfig{
+					"child": {ID: "T2"},
+				},
+			},
+			"ops": {ID: "T3"},
+		},
+		Repositories: map[config.RepositoryName]config.Repository{
+			"cilium": {
+				"ADMIN":      {"ops"},
+				"USER-ADMIN": {"alice", "bob"},
+			},
+			"docs": {
+				"ADMIN":      {"parent"},
+				"USER-ADMIN": {"bob"},
+			},
+		},
+	}
+	cfg.IndexTeams()
+	return cfg
+}
+
+func TestCheckProtections(t *testing.T) {
+	tests := []struct {
+		name      string
+		protected config.Protected
+		// change applies the changes to push to the local configuration.
+		change func(cfg *config.Config)
+		// pushRepos, pushMembers and pushTeams are all true unless skip is
+		// set.
+		skip string
+		want []string
+	}{
+		{
+			name:      "no changes",
+			protected: config.Protected{Members: []string{"owner"}, Teams: []string{"ops"}, Repositories: []config.RepositoryName{"cilium"}},
+			change:    func(cfg *config.Config) {},
+		},
+		{
+			name:      "removed protected member",
+			protected: config.Protected{Members: []string{"owner"}},
+			change:    func(cfg *config.Config) { delete(cfg.Members, "owner") },
+			want:      []string{`remove member "owner" from the organization: blocked by protected member "owner"`},
+		},
+		{
+			name:      "removed member not protected",
+			protected: config.Protected{Members: []string{"owner"}},
+			change:    func(cfg *config.Config) { delete(cfg.Members, "alice") },
+		},
+		{
+			name:      "removed protected member without pushing members",
+			protected: config.Protected{Members: []string{"owner"}},
+			change:    func(cfg *config.Config) { delete(cfg.Members, "owner") },
+			skip:      "members",
+		},
+		{
+			name:      "demoted protected owner",
+			protected: config.Protected{Members: []string{"owner"}},
+			change: func(cfg *config.Config) {
+				cfg.Members["owner"] = config.User{ID: "U1", Role: config.OrgRoleMember}
+			},
+			want: []string{`demote owner "owner" to a regular member of the organization: blocked by protected member "owner"`},
+		},
+		{
+			name:      "owner without a managed role",
+			protected: config.Protected{Members: []string{"owner"}},
+			change:    func(cfg *config.Config) { cfg.Members["owner"] = config.User{ID: "U1"} },
+		},
+		{
+			name:      "deleted protected team",
+			protected: config.Protected{Teams: []string{"ops"}},
+			change:    func(cfg *config.Config) { delete(cfg.Teams, "ops") },
+			skip:      "repositories",
+			want:      []string{`delete team "ops": blocked by protected team "ops"`},
+		},
+		{
+			name:      "deleted parent of a protected team",
+			protected: config.Protected{Teams: []string{"child"}},
+			change:    func(cfg *config.Config) { delete(cfg.Teams, "parent") },
+			skip:      "repositories",
+			want: []string{
+				`delete team "child": blocked by protected team "child"`,
+				`delete team "parent", parent of "child": blocked by protected team "child"`,
+			},
+		},
+		{
+			name:      "deleted protected team without pushing teams",
+			protected: config.Protected{Teams: []string{"ops"}},
+			change: func(cfg *config.Config) {
+				delete(cfg.Teams, "ops")
+				delete(cfg.Repositories["cilium"], "ADMIN")
+			},
+			skip: "teams",
+			want: []string{`remove admin permission of team "ops" from repository "cilium": blocked by protected team "ops"`},
+		},
+		{
+			name:      "renamed protected team",
+			protected: config.Protected{Teams: []string{"ops"}},
+			change: func(cfg *config.Config) {
+				cfg.Teams["sre"] = cfg.Teams["ops"]
+				delete(cfg.Teams, "ops")
+				cfg.Repositories["cilium"]["ADMIN"] = []config.TeamOrMemberName{"sre"}
+			},
+		},
+		{
+			name:      "renamed protected team losing its admin permission",
+			protected: config.Protected{Teams: []string{"sre"}},
+			change: func(cfg *config.Config) {
+				cfg.Teams["sre"] = cfg.Teams["ops"]
+				delete(cfg.Teams, "ops")
+				delete(cfg.Repositories["cilium"], "ADMIN")
+			},
+			want: []string{`remove admin permission of team "ops" from repository "cilium": blocked by protected team "sre"`},
+		},
+		{
+			name:      "renamed child of a deleted team",
+			protected: config.Protected{Teams: []string{"kid"}},
+			change: func(cfg *config.Config) {
+				cfg.Teams["kid"] = cfg.Teams["parent"].Children["child"]
+				delete(cfg.Teams, "parent")
+			},
+			skip: "repositories",
+			want: []string{`delete team "parent", parent of "child": blocked by protected team "kid"`},
+		},
+		{
+			name:      "demoted protected member in a repository",
+			protected: config.Protected{Members: []string{"alice"}},
+			change: func(cfg *config.Config) {
+				cfg.Repositories["cilium"]["USER-ADMIN"] = []config.TeamOrMemberName{"bob"}
+				cfg.Repositories["cilium"]["USER-WRITE"] = []config.TeamOrMemberName{"alice"}
+			},
+			want: []string{`remove admin permission of member "alice" from repository "cilium": blocked by protected member "alice"`},
+		},
+		{
+			name:      "admin permissions removed from a protected repository",
+			protected: config.Protected{Repositories: []config.RepositoryName{"cilium"}},
+			change: func(cfg *config.Config) {
+				delete(cfg.Repositories["cilium"], "ADMIN")
+				delete(cfg.Repositories["docs"], "ADMIN")
+				cfg.Repositories["cilium"]["USER-ADMIN"] = []config.TeamOrMemberName{"alice"}
+			},
+			want: []string{
+				`remove admin permission of member "bob" from repository "cilium": blocked by protected repository "cilium"`,
+				`remove admin permission of team "ops" from repository "cilium": blocked by protected repository "cilium"`,
+			},
+		},
+		{
+			name:      "admin permissions removed from a protected repository without pushing repositories",
+			protected: config.Protected{Repositories: []config.RepositoryName{"cilium"}},
+			change:    func(cfg *config.Config) { delete(cfg.Repositories, "cilium") },
+			skip:      "repositories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localCfg, upstreamCfg := protectionsConfig(), protectionsConfig()
+			tt.change(localCfg)
+			localCfg.IndexTeams()
+			localCfg.Protected = tt.protected
+
+			violations := checkProtections(localCfg, upstreamCfg, tt.skip != "repositories", tt.skip != "members", tt.skip != "teams")
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkProtections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
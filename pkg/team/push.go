@@ -20,10 +20,12 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cilium/team-manager/pkg/comparator"
 	"github.com/cilium/team-manager/pkg/config"
 	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/journal"
 	"github.com/cilium/team-manager/pkg/slices"
 	"github.com/cilium/team-manager/pkg/terminal"
 	gh "github.com/google/go-github/v79/github"
@@ -31,12 +33,19 @@ import (
 )
 
 func (tm *Manager) pushRepositories(ctx context.Context, force, dryRun bool, localCfg, upstreamCfg *config.Config) error {
+	var errs PermissionErrors
 	for repo := range localCfg.Repositories {
 		err := tm.pushPermissions(ctx, force, dryRun, string(repo), localCfg, upstreamCfg)
 		if err != nil {
-			return fmt.Errorf("unable to sync repository %q: %w", repo, err)
+			errs, err = tm.collectPermissionErrors(errs, err)
+			if err != nil {
+				return fmt.Errorf("unable to sync repository %q: %w", repo, err)
+			}
 		}
 	}
+	if len(errs) != 0 {
+		return errs
+	}
 
 	return nil
 }
@@ -133,8 +142,11 @@ func (tm *Manager) pushTeamsConfig(ctx context.Context, force, dryRun bool, loca
 	}
 
 	for teamName, teamCfg := range teamsChangedOnGH {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindEditTeam, Team: teamName}) {
+			continue
+		}
 		removeParent := teamCfg.ParentTeamID == nil
-		_, _, err := tm.ghClient.Teams.EditTeamBySlug(ctx, tm.owner, slug(teamName), *teamCfg, removeParent)
+		_, _, err := tm.ghClient.Teams.EditTeamBySlug(ctx, tm.owner, tm.teamSlug(teamName), *teamCfg, removeParent)
 
 		if err != nil {
 			return fmt.Errorf("unable to update team %s: %w", teamName, err)
@@ -170,6 +182,10 @@ func (tm *Manager) pushTeamsConfig(ctx context.Context, force, dryRun bool, loca
 				parentTeam.Children[teamName] = localTeam
 			}
 		}
+
+		if err := tm.record(journal.Operation{Kind: journal.KindEditTeam, Team: teamName, Previous: tm.previousTeamState(teamName)}); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -200,29 +216,41 @@ func (tm *Manager) pushTeams(ctx context.Context, force, dryRun bool, localCfg,
 		return nil
 	}
 
+	renames, err := teamRenames(localCfg, upstreamCfg)
+	if err != nil {
+		return err
+	}
+	renamedTeams := make([]string, 0, len(renames))
+	var renamesDesc []string
+	for oldName, newName := range renames {
+		renamedTeams = append(renamedTeams, oldName, newName)
+		renamesDesc = append(renamesDesc, fmt.Sprintf("%s -> %s", oldName, newName))
+	}
+	sort.Strings(renamesDesc)
+
 	teams := teamChange{}
 
 	cmp := comparator.CompareWithNames(localTeams, upstreamTeams, "local", "remote")
 	fmt.Printf("Local team config out of sync with upstream: %s\n", cmp)
-	toAdd := slices.NotIn(localTeams, upstreamTeams)
-	toDel := slices.NotIn(upstreamTeams, localTeams)
+	toAdd := slices.NotIn(slices.NotIn(localTeams, upstreamTeams), renamedTeams)
+	toDel := slices.NotIn(slices.NotIn(upstreamTeams, localTeams), renamedTeams)
 	teams.add = toAdd
 	teams.remove = toDel
 
-	if len(teams.add) == 0 && len(teams.remove) == 0 {
+	if len(teams.add) == 0 && len(teams.remove) == 0 && len(renames) == 0 {
 		return nil
 	}
 
 	fmt.Printf("Going to submit the following changes:\n")
 	fmt.Printf("    Adding teams: %s\n", strings.Join(teams.add, ", "))
 	fmt.Printf("  Removing teams: %s\n", strings.Join(teams.remove, ", "))
+	fmt.Printf("  Renaming teams: %s\n", strings.Join(renamesDesc, ", "))
 
 	if dryRun {
 		fmt.Printf("Skipping confirmation due to dry run. No changes will be made into GitHub\n")
 		return nil
 	}
 	yes := force
-	var err error
 	if !force {
 		yes, err = terminal.AskForConfirmation("Continue?")
 		if err != nil {
@@ -233,6 +261,18 @@ func (tm *Manager) pushTeams(ctx context.Context, force, dryRun bool, localCfg,
 		return nil
 	}
 
+	// Rename teams first so that the teams being removed or added can't
+	// conflict with their names.
+	for _, oldName := range slices.NotIn(upstreamTeams, localTeams) {
+		newName, ok := renames[oldName]
+		if !ok {
+			continue
+		}
+		if err := tm.renameTeam(ctx, localCfg, upstreamCfg, oldName, newName); err != nil {
+			return err
+		}
+	}
+
 	if len(teams.remove) != 0 {
 		teamsToRemove := map[string]struct{}{}
 		deletedTeams := map[string]struct{}{}
@@ -359,21 +399,41 @@ func (tm *Manager) AddTeams(ctx context.Context, localCfg *config.Config, add []
 		// Populate the ID fields from upstream.
 		team.ID = t.GetNodeID()
 		team.RESTID = t.GetID()
+		team.Slug = t.GetSlug()
+		tm.setTeamSlug(teamName, t.GetSlug())
 
 		teamsAdded = append(teamsAdded, t)
+
+		if err := tm.record(journal.Operation{Kind: journal.KindCreateTeam, Team: teamName}); err != nil {
+			return nil, err
+		}
 	}
 
 	return teamsAdded, nil
 }
 
-func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, localCfg, upstreamCfg *config.Config) error {
-	type teamChange struct {
-		add, remove []string
-	}
+// teamMembershipChange are the changes to the members of a team.
+type teamMembershipChange struct {
+	add, remove []string
+	// promote and demote are the members that become, or stop being,
+	// maintainers of the team. Members being added are promoted while added.
+	promote, demote []string
+}
 
-	teamChanges := map[string]teamChange{}
+func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, localCfg, upstreamCfg *config.Config) error {
+	teamChanges := map[string]teamMembershipChange{}
+	now := time.Now()
 
 	for localTeamName, localTeam := range localCfg.AllTeams {
+		// Members whose membership expired are removed from the team.
+		localMembers := localTeam.ActiveMembers(now)
+		localMaintainers := localTeam.ActiveMaintainers(now)
+		for _, member := range localTeam.Members {
+			if localTeam.MembershipExpired(member, now) {
+				fmt.Printf("Membership of %q in team %q expired on %s\n", member, localTeamName, localTeam.MembersUntil[member])
+			}
+		}
+
 		// Since we can't get the list of excluded members from GH we have
 		// to back it up and re-added it again at the end of this for-loop.
 		backExcludedMembers := localTeam.CodeReviewAssignment.ExcludedMembers
@@ -385,37 +445,47 @@ func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, l
 		upstreamTeam := upstreamCfg.AllTeams[localTeamName]
 		// An entire new team was added, so we will add the team members.
 		if upstreamTeam == nil {
-			tc := teamChange{
-				add: localTeam.Members,
+			tc := teamMembershipChange{
+				add:     localMembers,
+				promote: localMaintainers,
 			}
-			// When creating teams the authenticated user will become a member
-			// of that team. We will need to Remove it from the team if it's not
-			// meant to be added.
+			// When creating teams the authenticated user will become a
+			// maintainer of that team. We will need to Remove it from the team
+			// if it's not meant to be added, or demote it if it's not meant to
+			// be a maintainer.
 			if tm.AuthenticatedUser != "" {
 				var memberAdded bool
-				for _, membersToAdd := range localTeam.Members {
+				for _, membersToAdd := range localMembers {
 					if membersToAdd == tm.AuthenticatedUser {
 						memberAdded = true
 					}
 				}
 				if !memberAdded {
 					tc.remove = []string{tm.AuthenticatedUser}
+				} else if len(slices.NotIn([]string{tm.AuthenticatedUser}, localMaintainers)) != 0 {
+					tc.demote = []string{tm.AuthenticatedUser}
 				}
 			}
 			teamChanges[localTeamName] = tc
 		} else {
-			if (len(localTeam.Members) != 0 || len(upstreamTeam.Members) != 0) &&
-				!reflect.DeepEqual(localTeam.Members, upstreamTeam.Members) {
-				cmp := comparator.CompareWithNames(localTeam.Members, upstreamTeam.Members, "local", "remote")
+			tc := teamMembershipChange{}
+			if (len(localMembers) != 0 || len(upstreamTeam.Members) != 0) &&
+				!reflect.DeepEqual(localMembers, upstreamTeam.Members) {
+				cmp := comparator.CompareWithNames(localMembers, upstreamTeam.Members, "local", "remote")
 				fmt.Printf("Local team membership config out of sync with upstream: %s\n", cmp)
-				toAdd := slices.NotIn(localTeam.Members, upstreamTeam.Members)
-				toDel := slices.NotIn(upstreamTeam.Members, localTeam.Members)
-				if len(toAdd) != 0 || len(toDel) != 0 {
-					teamChanges[localTeamName] = teamChange{
-						add:    toAdd,
-						remove: toDel,
-					}
-				}
+				tc.add = slices.NotIn(localMembers, upstreamTeam.Members)
+				tc.remove = slices.NotIn(upstreamTeam.Members, localMembers)
+			}
+			if (len(localMaintainers) != 0 || len(upstreamTeam.Maintainers) != 0) &&
+				!reflect.DeepEqual(localMaintainers, upstreamTeam.Maintainers) {
+				cmp := comparator.CompareWithNames(localMaintainers, upstreamTeam.Maintainers, "local", "remote")
+				fmt.Printf("Local team maintainers config out of sync with upstream: %s\n", cmp)
+				tc.promote = slices.NotIn(localMaintainers, upstreamTeam.Maintainers)
+				// Maintainers removed from the team don't need to be demoted.
+				tc.demote = slices.NotIn(slices.NotIn(upstreamTeam.Maintainers, localMaintainers), tc.remove)
+			}
+			if len(tc.add) != 0 || len(tc.remove) != 0 || len(tc.promote) != 0 || len(tc.demote) != 0 {
+				teamChanges[localTeamName] = tc
 			}
 		}
 		localTeam.CodeReviewAssignment.ExcludedMembers = backExcludedMembers
@@ -431,6 +501,10 @@ func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, l
 		fmt.Printf(" Team: %s\n", teamName)
 		fmt.Printf("    Adding members: %s\n", strings.Join(teamCfg.add, ", "))
 		fmt.Printf("  Removing members: %s\n", strings.Join(teamCfg.remove, ", "))
+		if len(teamCfg.promote) != 0 || len(teamCfg.demote) != 0 {
+			fmt.Printf(" Promoting maintainers: %s\n", strings.Join(teamCfg.promote, ", "))
+			fmt.Printf("  Demoting maintainers: %s\n", strings.Join(teamCfg.demote, ", "))
+		}
 	}
 	if dryRun {
 		fmt.Printf("Skipping confirmation due to dry run. No changes will be made into GitHub\n")
@@ -450,7 +524,7 @@ func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, l
 	}
 
 	for teamName, teamCfg := range teamChanges {
-		if err := tm.pushTeamMembers(ctx, teamName, teamCfg.add, teamCfg.remove); err != nil {
+		if err := tm.pushTeamMembers(ctx, teamName, teamCfg); err != nil {
 			return fmt.Errorf("unable to sync team %s: %w\n", teamName, err)
 		}
 		teamMembers := map[string]struct{}{}
@@ -468,6 +542,10 @@ func (tm *Manager) pushTeamMembership(ctx context.Context, force, dryRun bool, l
 		for teamMember := range teamMembers {
 			team.Members = append(team.Members, teamMember)
 		}
+		// Removed members, e.g. due to an expired membership, are no longer
+		// maintainers nor mentors of the team.
+		team.Maintainers = slices.NotIn(team.Maintainers, teamCfg.remove)
+		team.Mentors = slices.NotIn(team.Mentors, teamCfg.remove)
 		localCfg.AllTeams[teamName] = team
 	}
 
@@ -499,9 +577,14 @@ func (tm *Manager) pushCodeReviewAssignments(ctx context.Context, localCfg *conf
 	sort.Strings(teamNames)
 
 	for _, teamName := range teamNames {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindUpdateCodeReview, Team: teamName}) {
+			continue
+		}
 		storedTeam := localCfg.AllTeams[teamName]
 		cra := storedTeam.CodeReviewAssignment
-		usersIDs := getExcludedUsers(teamName, localCfg.Members, storedTeam.Mentors, cra.ExcludedMembers, localCfg.ExcludeCRAFromAllTeams)
+		// Members whose exclusion expired are assigned reviews again.
+		excludedMembers := config.ActiveExcludedMembers(cra.ExcludedMembers, time.Now())
+		usersIDs := getExcludedUsers(teamName, localCfg.Members, storedTeam.Mentors, excludedMembers, localCfg.ExcludeCRAFromAllTeams)
 
 		input := github.UpdateTeamReviewAssignmentInput{
 			Algorithm:             cra.Algorithm,
@@ -521,6 +604,9 @@ func (tm *Manager) pushCodeReviewAssignments(ctx context.Context, localCfg *conf
 		if err != nil {
 			return fmt.Errorf("unable to sync team excluded members %s: %w\n", teamName, err)
 		}
+		if err := tm.record(journal.Operation{Kind: journal.KindUpdateCodeReview, Team: teamName, Previous: tm.previousCodeReviewState(teamName)}); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -529,6 +615,8 @@ func (tm *Manager) pushCodeReviewAssignments(ctx context.Context, localCfg *conf
 func (tm *Manager) pushMembers(ctx context.Context, force, dryRun bool, localCfg, upstreamCfg *config.Config) error {
 	type membersChange struct {
 		add, remove []string
+		// roles are the new roles of existing members, indexed by login.
+		roles map[string]config.OrgRole
 	}
 
 	// Get a list of all organization members from the local config
@@ -550,26 +638,38 @@ func (tm *Manager) pushMembers(ctx context.Context, force, dryRun bool, localCfg
 	}
 	sort.Strings(upstreamMembers)
 
-	membersChanges := membersChange{}
+	membersChanges := membersChange{
+		roles: map[string]config.OrgRole{},
+	}
 
-	if reflect.DeepEqual(localMembers, upstreamMembers) {
-		return nil
+	// Members without a role in the local config don't have their role
+	// managed by team-manager.
+	var roleChanges []string
+	for login, localUser := range localCfg.Members {
+		upstreamUser, ok := upstreamCfg.Members[login]
+		if !ok || localUser.Role == "" || localUser.Role == upstreamUser.Role {
+			continue
+		}
+		membersChanges.roles[login] = localUser.Role
+		roleChanges = append(roleChanges, fmt.Sprintf("%s (%s -> %s)", login, upstreamUser.Role, localUser.Role))
 	}
+	sort.Strings(roleChanges)
 
-	cmp := comparator.CompareWithNames(localMembers, upstreamMembers, "local", "remote")
-	fmt.Printf("Local members config out of sync with upstream: %s\n", cmp)
-	toAdd := slices.NotIn(localMembers, upstreamMembers)
-	toDel := slices.NotIn(upstreamMembers, localMembers)
-	membersChanges.add = toAdd
-	membersChanges.remove = toDel
+	if !reflect.DeepEqual(localMembers, upstreamMembers) {
+		cmp := comparator.CompareWithNames(localMembers, upstreamMembers, "local", "remote")
+		fmt.Printf("Local members config out of sync with upstream: %s\n", cmp)
+		membersChanges.add = slices.NotIn(localMembers, upstreamMembers)
+		membersChanges.remove = slices.NotIn(upstreamMembers, localMembers)
+	}
 
-	if len(membersChanges.add) == 0 && len(membersChanges.remove) == 0 {
+	if len(membersChanges.add) == 0 && len(membersChanges.remove) == 0 && len(membersChanges.roles) == 0 {
 		return nil
 	}
 
 	fmt.Printf("Going to submit the following changes:\n")
 	fmt.Printf("    Adding members: %s\n", strings.Join(membersChanges.add, ", "))
 	fmt.Printf("  Removing members: %s\n", strings.Join(membersChanges.remove, ", "))
+	fmt.Printf("    Changing roles: %s\n", strings.Join(roleChanges, ", "))
 
 	if dryRun {
 		fmt.Printf("Skipping confirmation due to dry run. No changes will be made into GitHub\n")
@@ -588,7 +688,7 @@ func (tm *Manager) pushMembers(ctx context.Context, force, dryRun bool, localCfg
 	}
 
 	if len(membersChanges.add) != 0 {
-		membersAdded, err := tm.AddOrgMembers(ctx, membersChanges.add)
+		membersAdded, err := tm.AddOrgMembers(ctx, localCfg.Members, membersChanges.add)
 		if err != nil {
 			return err
 		}
@@ -605,6 +705,21 @@ func (tm *Manager) pushMembers(ctx context.Context, force, dryRun bool, localCfg
 				ID:      user.GetNodeID(),
 				Name:    user.GetName(),
 				SlackID: localUser.SlackID,
+				Role:    localUser.Role,
+			}
+		}
+	}
+
+	if len(membersChanges.roles) != 0 {
+		logins := make([]string, 0, len(membersChanges.roles))
+		for login := range membersChanges.roles {
+			logins = append(logins, login)
+		}
+		sort.Strings(logins)
+		for _, login := range logins {
+			err := tm.SetOrgMemberRole(ctx, login, membersChanges.roles[login], upstreamCfg.Members[login].Role)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -617,28 +732,17 @@ func (tm *Manager) pushMembers(ctx context.Context, force, dryRun bool, localCfg
 			return err
 		}
 		// Since members were removed from the org, they were also
-		// removed from teams. Thus, Remove them from the local config
-		// as well in the respective teams and repositories.
+		// removed from teams and repositories. Thus, Remove them from the
+		// local config as well in the respective teams and repositories,
+		// except for the repositories of outside collaborators, which are
+		// granted again when pushing the repositories.
 		for _, member := range membersChanges.remove {
-			delete(localCfg.Members, member)
-
-			for _, team := range localCfg.AllTeams {
-				team.Members = slices.Remove(team.Members, member)
-				team.Mentors = slices.Remove(team.Mentors, member)
-				team.CodeReviewAssignment.ExcludedMembers = config.RemoveExcludedMember(team.CodeReviewAssignment.ExcludedMembers, member)
+			var keepRepos []config.RepositoryName
+			if _, ok := localCfg.Collaborators[member]; ok {
+				keepRepos = localCfg.UserRepositories(member)
 			}
-
-			for _, repo := range localCfg.Repositories {
-				for permission, users := range repo {
-					if permission.IsUser() {
-						repo[permission] = slices.Remove(users, config.TeamOrMemberName(member))
-						if len(repo[permission]) == 0 {
-							delete(repo, permission)
-						}
-					}
-				}
-			}
-			localCfg.ExcludeCRAFromAllTeams = slices.Remove(localCfg.ExcludeCRAFromAllTeams, member)
+			localCfg.RemoveMember(member, keepRepos)
+			upstreamCfg.RemoveMember(member, nil)
 		}
 	}
 	return nil
@@ -763,71 +867,51 @@ func (tm *Manager) pushPermissionsMembership(ctx context.Context, force, dryRun
 		return nil
 	}
 
+	var errs PermissionErrors
 	if len(userPerms.remove) != 0 {
 		err := tm.PushRepositoryMembersPermissions(ctx, repoName, "", nil, userPerms.remove)
 		if err != nil {
-			return err
+			errs, err = tm.collectPermissionErrors(errs, err)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	if len(teamPerms.remove) != 0 {
 		err = tm.PushRepositoryTeamPermissions(ctx, repoName, "", nil, teamPerms.remove)
 		if err != nil {
-			return err
+			errs, err = tm.collectPermissionErrors(errs, err)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	if len(permissionsModified) != 0 {
 		for permission, users := range permissionsModified {
 			if permission.IsUser() {
-				err := tm.PushRepositoryMembersPermissions(ctx, repoName, permission.GetPermission(), users, nil)
-				if err != nil {
-					return err
-				}
+				err = tm.PushRepositoryMembersPermissions(ctx, repoName, permission.GetPermission(), users, nil)
 			} else {
 				err = tm.PushRepositoryTeamPermissions(ctx, repoName, permission.GetPermission(), users, nil)
+			}
+			if err != nil {
+				errs, err = tm.collectPermissionErrors(errs, err)
 				if err != nil {
 					return err
 				}
 			}
 		}
 	}
+	if len(errs) != 0 {
+		return errs
+	}
 	return nil
 }
 
 func (tm *Manager) pushPermissions(ctx context.Context, force, dryRun bool, repoName string, localCfg, upstreamCfg *config.Config) error {
-	localRepo := localCfg.Repositories[config.RepositoryName(repoName)]
-
 	// Get all teams and users for this repository
-	localUsers := map[config.TeamOrMemberName]config.Permission{}
-	localTeams := map[config.TeamOrMemberName]config.Permission{}
-	for localPerm, usersOrTeams := range localRepo {
-		if localPerm.IsUser() {
-			for _, user := range usersOrTeams {
-				localUsers[user] = localPerm
-			}
-		} else {
-			for _, team := range usersOrTeams {
-				localTeams[team] = localPerm
-			}
-		}
-	}
-
-	upstreamRepo := upstreamCfg.Repositories[config.RepositoryName(repoName)]
-
-	// Get all teams and users for this repository
-	upstreamUsers := map[config.TeamOrMemberName]config.Permission{}
-	upstreamTeams := map[config.TeamOrMemberName]config.Permission{}
-	for upstreamPerm, usersOrTeams := range upstreamRepo {
-		if upstreamPerm.IsUser() {
-			for _, user := range usersOrTeams {
-				upstreamUsers[user] = upstreamPerm
-			}
-		} else {
-			for _, team := range usersOrTeams {
-				upstreamTeams[team] = upstreamPerm
-			}
-		}
-	}
+	localUsers, localTeams := repoPermissions(localCfg.Repositories[config.RepositoryName(repoName)])
+	upstreamUsers, upstreamTeams := repoPermissions(upstreamCfg.Repositories[config.RepositoryName(repoName)])
 
 	// Check if teams were added or removed, but not updated, in the repo.
 	err := tm.pushPermissionsMembership(ctx, force, dryRun, repoName, localCfg, localUsers, upstreamUsers, localTeams, upstreamTeams)
@@ -837,24 +921,68 @@ func (tm *Manager) pushPermissions(ctx context.Context, force, dryRun bool, repo
 	return nil
 }
 
-// pushTeamMembers adds and removes the given login names into the given team
-// name.
-func (tm *Manager) pushTeamMembers(ctx context.Context, teamName string, add, remove []string) error {
-	for _, user := range add {
-		fmt.Printf("Adding member %s to team %s\n", user, teamName)
-		if _, _, err := tm.ghClient.Teams.AddTeamMembershipBySlug(ctx, tm.owner, slug(teamName), user, &gh.TeamAddTeamMembershipOptions{Role: "member"}); err != nil {
+// pushTeamMembers adds, removes, promotes and demotes the given login names in
+// the given team name.
+func (tm *Manager) pushTeamMembers(ctx context.Context, teamName string, change teamMembershipChange) error {
+	for _, user := range change.add {
+		role := "member"
+		if len(slices.NotIn([]string{user}, change.promote)) == 0 {
+			role = "maintainer"
+		}
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindAddTeamMember, Team: teamName, Member: user, Role: role}) {
+			continue
+		}
+		fmt.Printf("Adding %s %s to team %s\n", role, user, teamName)
+		if _, _, err := tm.ghClient.Teams.AddTeamMembershipBySlug(ctx, tm.owner, tm.teamSlug(teamName), user, &gh.TeamAddTeamMembershipOptions{Role: role}); err != nil {
+			return err
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindAddTeamMember, Team: teamName, Member: user, Role: role}); err != nil {
 			return err
 		}
 	}
-	for _, user := range remove {
+	for _, user := range slices.NotIn(change.promote, change.add) {
+		if err := tm.setTeamMemberRole(ctx, teamName, user, "maintainer", "member"); err != nil {
+			return err
+		}
+	}
+	for _, user := range change.demote {
+		if err := tm.setTeamMemberRole(ctx, teamName, user, "member", "maintainer"); err != nil {
+			return err
+		}
+	}
+	for _, user := range change.remove {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindRemoveTeamMember, Team: teamName, Member: user}) {
+			continue
+		}
 		fmt.Printf("Removing member %s from team %s\n", user, teamName)
-		if _, err := tm.ghClient.Teams.RemoveTeamMembershipBySlug(ctx, tm.owner, slug(teamName), user); err != nil {
+		if _, err := tm.ghClient.Teams.RemoveTeamMembershipBySlug(ctx, tm.owner, tm.teamSlug(teamName), user); err != nil {
+			return err
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindRemoveTeamMember, Team: teamName, Member: user, Previous: tm.previousTeamMemberState(teamName, user)}); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// setTeamMemberRole changes the role of an existing member of the given team.
+func (tm *Manager) setTeamMemberRole(ctx context.Context, teamName, user, role, previousRole string) error {
+	if tm.alreadyApplied(journal.Operation{Kind: journal.KindSetTeamMemberRole, Team: teamName, Member: user, Role: role}) {
+		return nil
+	}
+	fmt.Printf("Setting role of member %s in team %s to %s\n", user, teamName, role)
+	if _, _, err := tm.ghClient.Teams.AddTeamMembershipBySlug(ctx, tm.owner, tm.teamSlug(teamName), user, &gh.TeamAddTeamMembershipOptions{Role: role}); err != nil {
+		return err
+	}
+	return tm.record(journal.Operation{
+		Kind:     journal.KindSetTeamMemberRole,
+		Team:     teamName,
+		Member:   user,
+		Role:     role,
+		Previous: &journal.State{Role: previousRole},
+	})
+}
+
 // pushCodeReviewAssignmentForTeam updates the review assignment into GH for the given
 // team name with the given team ID.
 func (tm *Manager) pushCodeReviewAssignmentForTeam(ctx context.Context, teamID githubv4.ID, input github.UpdateTeamReviewAssignmentInput) error {
@@ -869,23 +997,16 @@ func (tm *Manager) pushCodeReviewAssignmentForTeam(ctx context.Context, teamID g
 	return tm.gqlGHClient.Mutate(ctx, &m, input, nil)
 }
 
-func (tm *Manager) AddOrgMembers(ctx context.Context, add []string) ([]*gh.User, error) {
+// AddOrgMembers invites the given logins into the organization. The role of
+// the invitation is taken from members, defaulting to a regular member.
+func (tm *Manager) AddOrgMembers(ctx context.Context, members map[string]config.User, add []string) ([]*gh.User, error) {
 	var membersAdded []*gh.User
 	var allInvitations []*gh.Invitation
 	if len(add) != 0 {
-		page := 0
-		for {
-			invitations, resp, err := tm.ghClient.Organizations.ListPendingOrgInvitations(ctx, tm.owner, &gh.ListOptions{
-				Page: page,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("unable to get the list of pending org invitations: %w", err)
-			}
-			allInvitations = append(allInvitations, invitations...)
-			if resp.NextPage == 0 {
-				break
-			}
-			page = resp.NextPage
+		var err error
+		allInvitations, err = tm.listOrgInvitations(ctx, tm.ghClient.Organizations.ListPendingOrgInvitations)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the list of pending org invitations: %w", err)
 		}
 	}
 	for _, memberName := range add {
@@ -899,6 +1020,10 @@ func (tm *Manager) AddOrgMembers(ctx context.Context, add []string) ([]*gh.User,
 		if isInvited {
 			continue
 		}
+		role := members[memberName].Role
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindInviteOrgMember, Member: memberName, Role: string(role)}) {
+			continue
+		}
 
 		user, _, err := tm.ghClient.Users.Get(ctx, memberName)
 		if err != nil {
@@ -907,67 +1032,166 @@ func (tm *Manager) AddOrgMembers(ctx context.Context, add []string) ([]*gh.User,
 
 		_, _, err = tm.ghClient.Organizations.CreateOrgInvitation(ctx, tm.owner, &gh.CreateOrgInvitationOptions{
 			InviteeID: user.ID,
+			Role:      gh.Ptr(role.InvitationRole()),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("unable to invite user %q to the organization: %w", memberName, err)
 		}
 		membersAdded = append(membersAdded, user)
+		if err := tm.record(journal.Operation{Kind: journal.KindInviteOrgMember, Member: memberName, Role: string(role)}); err != nil {
+			return nil, err
+		}
 	}
 	return membersAdded, nil
 }
 
+// SetOrgMemberRole changes the role of an existing member of the
+// organization.
+func (tm *Manager) SetOrgMemberRole(ctx context.Context, login string, role, previousRole config.OrgRole) error {
+	if tm.alreadyApplied(journal.Operation{Kind: journal.KindSetOrgMemberRole, Member: login, Role: string(role)}) {
+		return nil
+	}
+	fmt.Printf("Setting organization role of member %s to %s\n", login, role)
+	_, _, err := tm.ghClient.Organizations.EditOrgMembership(ctx, login, tm.owner, &gh.Membership{
+		Role: gh.Ptr(string(role)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set the organization role of %q to %q: %w", login, role, err)
+	}
+	return tm.record(journal.Operation{
+		Kind:     journal.KindSetOrgMemberRole,
+		Member:   login,
+		Role:     string(role),
+		Previous: &journal.State{Role: string(previousRole)},
+	})
+}
+
 func (tm *Manager) RemoveOrgMembers(ctx context.Context, logins []string) error {
 	for _, login := range logins {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindRemoveOrgMember, Member: login}) {
+			continue
+		}
 		_, err := tm.ghClient.Organizations.RemoveMember(ctx, tm.owner, login)
 		if err != nil {
 			return err
 		}
+		if err := tm.record(journal.Operation{Kind: journal.KindRemoveOrgMember, Member: login, Previous: tm.previousMemberState(login)}); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (tm *Manager) RemoveOrgTeams(ctx context.Context, teamNames []string) error {
 	for _, teamName := range teamNames {
-		_, err := tm.ghClient.Teams.DeleteTeamBySlug(ctx, tm.owner, slug(teamName))
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindDeleteTeam, Team: teamName}) {
+			continue
+		}
+		_, err := tm.ghClient.Teams.DeleteTeamBySlug(ctx, tm.owner, tm.teamSlug(teamName))
 		if err != nil {
 			return err
 		}
+		if err := tm.record(journal.Operation{Kind: journal.KindDeleteTeam, Team: teamName, Previous: tm.previousTeamState(teamName)}); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// PushRepositoryTeamPermissions removes the permissions of the teams in remove
+// and grants perm to the teams in add, in the given repository. It returns
+// PermissionErrors with the changes that failed.
 func (tm *Manager) PushRepositoryTeamPermissions(ctx context.Context, repo string, perm string, add, remove []string) error {
+	var errs PermissionErrors
 	for _, team := range remove {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindRemoveTeamRepoPermission, Team: team, Repository: repo}) {
+			continue
+		}
 		fmt.Printf("Removing permissions for team %q in repo %q\n", team, repo)
-		if _, err := tm.ghClient.Teams.RemoveTeamRepoBySlug(ctx, tm.owner, slug(team), tm.owner, repo); err != nil {
-			fmt.Printf("[ERROR]: %s\n", err)
+		if resp, err := tm.ghClient.Teams.RemoveTeamRepoBySlug(ctx, tm.owner, tm.teamSlug(team), tm.owner, repo); err != nil {
+			permErr := newPermissionError(repo, team, true, "", resp, err)
+			fmt.Printf("[ERROR]: %s\n", permErr)
+			errs = append(errs, permErr)
+			if !tm.ContinueOnError {
+				return errs
+			}
+			continue
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindRemoveTeamRepoPermission, Team: team, Repository: repo, Previous: tm.previousRepoState(repo, team, false)}); err != nil {
+			return err
 		}
 	}
 	for _, team := range add {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindSetTeamRepoPermission, Team: team, Repository: repo, Permission: perm}) {
+			continue
+		}
 		fmt.Printf("Adding permission %q to team %q in repo %q\n", perm, team, repo)
-		if _, err := tm.ghClient.Teams.AddTeamRepoBySlug(ctx, tm.owner, slug(team), tm.owner, repo, &gh.TeamAddTeamRepoOptions{
+		if resp, err := tm.ghClient.Teams.AddTeamRepoBySlug(ctx, tm.owner, tm.teamSlug(team), tm.owner, repo, &gh.TeamAddTeamRepoOptions{
 			Permission: config.GraphQLPerm2RestAPIPerm(perm),
 		}); err != nil {
-			fmt.Printf("[ERROR]: %s\n", err)
+			permErr := newPermissionError(repo, team, true, perm, resp, err)
+			fmt.Printf("[ERROR]: %s\n", permErr)
+			errs = append(errs, permErr)
+			if !tm.ContinueOnError {
+				return errs
+			}
+			continue
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindSetTeamRepoPermission, Team: team, Repository: repo, Permission: perm, Previous: tm.previousRepoState(repo, team, false)}); err != nil {
+			return err
 		}
 	}
+	if len(errs) != 0 {
+		return errs
+	}
 	return nil
 }
 
+// PushRepositoryMembersPermissions removes the permissions of the users in
+// remove and grants perm to the users in add, in the given repository. It
+// returns PermissionErrors with the changes that failed.
 func (tm *Manager) PushRepositoryMembersPermissions(ctx context.Context, repo, perm string, add, remove []string) error {
+	var errs PermissionErrors
 	for _, user := range remove {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindRemoveUserRepoPermission, Member: user, Repository: repo}) {
+			continue
+		}
 		fmt.Printf("Removing permission for member %q in repo %q\n", user, repo)
-		if _, err := tm.ghClient.Repositories.RemoveCollaborator(ctx, tm.owner, repo, user); err != nil {
-			fmt.Printf("[ERROR]: %s\n", err)
+		if resp, err := tm.ghClient.Repositories.RemoveCollaborator(ctx, tm.owner, repo, user); err != nil {
+			permErr := newPermissionError(repo, user, false, "", resp, err)
+			fmt.Printf("[ERROR]: %s\n", permErr)
+			errs = append(errs, permErr)
+			if !tm.ContinueOnError {
+				return errs
+			}
+			continue
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindRemoveUserRepoPermission, Member: user, Repository: repo, Previous: tm.previousRepoState(repo, user, true)}); err != nil {
+			return err
 		}
 	}
 	for _, user := range add {
+		if tm.alreadyApplied(journal.Operation{Kind: journal.KindSetUserRepoPermission, Member: user, Repository: repo, Permission: perm}) {
+			continue
+		}
 		fmt.Printf("Adding permission %q to member %q in repo %q\n", perm, user, repo)
-		if _, _, err := tm.ghClient.Repositories.AddCollaborator(ctx, tm.owner, repo, user, &gh.RepositoryAddCollaboratorOptions{
+		if _, resp, err := tm.ghClient.Repositories.AddCollaborator(ctx, tm.owner, repo, user, &gh.RepositoryAddCollaboratorOptions{
 			Permission: config.GraphQLPerm2RestAPIPerm(perm),
 		}); err != nil {
-			fmt.Printf("[ERROR]: %s\n", err)
+			permErr := newPermissionError(repo, user, false, perm, resp, err)
+			fmt.Printf("[ERROR]: %s\n", permErr)
+			errs = append(errs, permErr)
+			if !tm.ContinueOnError {
+				return errs
+			}
+			continue
+		}
+		if err := tm.record(journal.Operation{Kind: journal.KindSetUserRepoPermission, Member: user, Repository: repo, Permission: perm, Previous: tm.previousRepoState(repo, user, true)}); err != nil {
+			return err
 		}
 	}
+	if len(errs) != 0 {
+		return errs
+	}
 	return nil
 }
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestPushTeamMembership(t *testing.T) {
+	expired := config.NewDate(time.Now().AddDate(0, 0, -2))
+	tests := []struct {
+		name string
+		// local and upstream are the team "sig-foo" in the local
+		// configuration and in GitHub. upstream is nil if the team is new.
+		local, upstream *config.TeamConfig
+		wantRequests    []string
+		// wantMembers and wantMaintainers are the members and maintainers of
+		// the team in the local configuration once pushed.
+		wantMembers, wantMaintainers []string
+	}{
+		{
+			name:            "in sync",
+			local:           &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"alice"}},
+			upstream:        &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"alice"}},
+			wantMembers:     []string{"alice", "bob"},
+			wantMaintainers: []string{"alice"},
+		},
+		{
+			name:  "new team",
+			local: &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"bob"}},
+			wantRequests: []string{
+				`PUT /orgs/cilium/teams/sig-foo/memberships/alice {"role":"member"}`,
+				`PUT /orgs/cilium/teams/sig-foo/memberships/bob {"role":"maintainer"}`,
+				"DELETE /orgs/cilium/teams/sig-foo/memberships/bot",
+			},
+			wantMembers:     []string{"alice", "bob"},
+			wantMaintainers: []string{"bob"},
+		},
+		{
+			name:  "new team with the authenticated user",
+			local: &config.TeamConfig{Members: []string{"alice", "bot"}, Maintainers: []string{"alice"}},
+			wantRequests: []string{
+				`PUT /orgs/cilium/teams/sig-foo/memberships/alice {"role":"maintainer"}`,
+				`PUT /orgs/cilium/teams/sig-foo/memberships/bot {"role":"member"}`,
+				`PUT /orgs/cilium/teams/sig-foo/memberships/bot {"role":"member"}`,
+			},
+			wantMembers:     []string{"alice", "bot"},
+			wantMaintainers: []string{"alice"},
+		},
+		{
+			name:     "promoted and demoted members",
+			local:    &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"alice"}},
+			upstream: &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"bob"}},
+			wantRequests: []string{
+				`PUT /orgs/cilium/teams/sig-foo/memberships/alice {"role":"maintainer"}`,
+				`PUT /orgs/cilium/teams/sig-foo/memberships/bob {"role":"member"}`,
+			},
+			wantMembers:     []string{"alice", "bob"},
+			wantMaintainers: []string{"alice"},
+		},
+		{
+			name:     "removed maintainer isn't demoted",
+			local:    &config.TeamConfig{Members: []string{"alice", "carol"}, Maintainers: []string{"carol"}},
+			upstream: &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"bob"}},
+			wantRequests: []string{
+				`PUT /orgs/cilium/teams/sig-foo/memberships/carol {"role":"maintainer"}`,
+				"DELETE /orgs/cilium/teams/sig-foo/memberships/bob",
+			},
+			wantMembers:     []string{"alice", "carol"},
+			wantMaintainers: []string{"carol"},
+		},
+		{
+			name: "expired maintainer",
+			local: &config.TeamConfig{
+				Members:      []string{"alice", "bob"},
+				Maintainers:  []string{"bob"},
+				MembersUntil: map[string]config.Date{"bob": expired},
+			},
+			upstream: &config.TeamConfig{Members: []string{"alice", "bob"}, Maintainers: []string{"bob"}},
+			wantRequests: []string{
+				"DELETE /orgs/cilium/teams/sig-foo/memberships/bob",
+			},
+			wantMembers:     []string{"alice"},
+			wantMaintainers: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, requests := fakeGitHub(t)
+			tm.AuthenticatedUser = "bot"
+			localCfg := &config.Config{Teams: map[string]*config.TeamConfig{"sig-foo": tt.local}}
+			localCfg.IndexTeams()
+			upstreamCfg := &config.Config{Teams: map[string]*config.TeamConfig{}}
+			if tt.upstream != nil {
+				upstreamCfg.Teams["sig-foo"] = tt.upstream
+			}
+			upstreamCfg.IndexTeams()
+
+			if err := tm.pushTeamMembership(context.Background(), true, false, localCfg, upstreamCfg); err != nil {
+				t.Fatalf("pushTeamMembership() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", *requests, tt.wantRequests)
+			}
+			team := localCfg.AllTeams["sig-foo"]
+			if err := config.SortConfig(localCfg); err != nil {
+				t.Fatalf("SortConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(team.Members, tt.wantMembers) {
+				t.Errorf("members = %q, want %q", team.Members, tt.wantMembers)
+			}
+			if !reflect.DeepEqual(team.Maintainers, tt.wantMaintainers) {
+				t.Errorf("maintainers = %q, want %q", team.Maintainers, tt.wantMaintainers)
+			}
+		})
+	}
+}
+
+func TestPushMembersRoles(t *testing.T) {
+	tests := []struct {
+		name string
+		// local and upstream are the roles of the members in the local
+		// configuration and in GitHub.
+		local, upstream map[string]config.OrgRole
+		wantRequests    []string
+	}{
+		{
+			name:     "in sync",
+			local:    map[string]config.OrgRole{"alice": config.OrgRoleAdmin, "bob": config.OrgRoleMember},
+			upstream: map[string]config.OrgRole{"alice": config.OrgRoleAdmin, "bob": config.OrgRoleMember},
+		},
+		{
+			name:     "roles not managed",
+			local:    map[string]config.OrgRole{"alice": "", "bob": ""},
+			upstream: map[string]config.OrgRole{"alice": config.OrgRoleAdmin, "bob": config.OrgRoleMember},
+		},
+		{
+			name:     "promoted and demoted members",
+			local:    map[string]config.OrgRole{"alice": config.OrgRoleMember, "bob": config.OrgRoleAdmin, "carol": ""},
+			upstream: map[string]config.OrgRole{"alice": config.OrgRoleAdmin, "bob": config.OrgRoleMember, "carol": config.OrgRoleAdmin},
+			wantRequests: []string{
+				`PUT /orgs/cilium/memberships/alice {"role":"member"}`,
+				`PUT /orgs/cilium/memberships/bob {"role":"admin"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, requests := fakeGitHub(t)
+			localCfg := &config.Config{Members: map[string]config.User{}}
+			for login, role := range tt.local {
+				localCfg.Members[login] = config.User{ID: login, Role: role}
+			}
+			upstreamCfg := &config.Config{Members: map[string]config.User{}}
+			for login, role := range tt.upstream {
+				upstreamCfg.Members[login] = config.User{ID: login, Role: role}
+			}
+
+			if err := tm.pushMembers(context.Background(), true, false, localCfg, upstreamCfg); err != nil {
+				t.Fatalf("pushMembers() error = %v", err)
+			}
+			if !reflect.DeepEqual(*requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", *requests, tt.wantRequests)
+			}
+		})
+	}
+}
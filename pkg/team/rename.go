@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gh "github.com/google/go-github/v79/github"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/journal"
+)
+
+// teamRenames returns the teams renamed in the local configuration, mapping
+// their upstream name to their local name. Renames are detected by the ID of
+// the teams, which doesn't change when a team is renamed in GitHub.
+func teamRenames(localCfg, upstreamCfg *config.Config) (map[string]string, error) {
+	upstreamNames := make(map[string]string, len(upstreamCfg.AllTeams))
+	for teamName, team := range upstreamCfg.AllTeams {
+		if team.ID != "" {
+			upstreamNames[team.ID] = teamName
+		}
+	}
+
+	renames := map[string]string{}
+	for localName, localTeam := range localCfg.AllTeams {
+		if localTeam.ID == "" {
+			continue
+		}
+		if _, ok := upstreamCfg.AllTeams[localName]; ok {
+			continue
+		}
+		upstreamName, ok := upstreamNames[localTeam.ID]
+		if !ok {
+			continue
+		}
+		if _, ok := localCfg.AllTeams[upstreamName]; ok {
+			return nil, fmt.Errorf("teams %q and %q have the same ID %q, remove the ID of the team that should be created", upstreamName, localName, localTeam.ID)
+		}
+		if otherName, ok := renames[upstreamName]; ok {
+			return nil, fmt.Errorf("teams %q and %q have the same ID %q, remove the ID of the team that should be created", otherName, localName, localTeam.ID)
+		}
+		renames[upstreamName] = localName
+	}
+	return renames, nil
+}
+
+// renameTeam renames the team oldName into newName in GitHub and in the
+// configurations.
+func (tm *Manager) renameTeam(ctx context.Context, localCfg, upstreamCfg *config.Config, oldName, newName string) error {
+	fmt.Printf("Renaming team %s to %s\n", oldName, newName)
+	t, _, err := tm.ghClient.Teams.EditTeamBySlug(ctx, tm.owner, tm.teamSlug(oldName), gh.NewTeam{
+		Name: newName,
+	}, false)
+	if err != nil {
+		return fmt.Errorf("unable to rename team %q to %q: %w", oldName, newName, err)
+	}
+	delete(tm.slugs, oldName)
+	tm.setTeamSlug(newName, t.GetSlug())
+
+	if err := upstreamCfg.RenameTeam(oldName, newName); err != nil {
+		return err
+	}
+	upstreamCfg.AllTeams[newName].Slug = t.GetSlug()
+	if localTeam, ok := localCfg.AllTeams[newName]; ok {
+		localTeam.Slug = t.GetSlug()
+	}
+
+	return tm.record(journal.Operation{
+		Kind:     journal.KindRenameTeam,
+		Team:     newName,
+		Previous: &journal.State{Name: oldName},
+	})
+}
+
+// renamedMembers returns a description of the members of the local
+// configuration that were renamed in GitHub, along with the command to migrate
+// their login.
+func renamedMembers(localCfg, upstreamCfg *config.Config) []string {
+	var renamed []string
+	for oldLogin, newLogin := range config.LoginRenames(localCfg, upstreamCfg) {
+		renamed = append(renamed, fmt.Sprintf("%s -> %s: run 'team-manager migrate-login %s %s'", oldLogin, newLogin, oldLogin, newLogin))
+	}
+	sort.Strings(renamed)
+	return renamed
+}
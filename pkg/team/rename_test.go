@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+func TestTeamRenames(t *testing.T) {
+	tests := []struct {
+		name            string
+		local, upstream map[string]*config.TeamConfig
+		want            map[string]string
+		wantErr         string
+	}{
+		{
+			name:     "no renames",
+			local:    map[string]*config.TeamConfig{"sig-foo": {ID: "T1"}},
+			upstream: map[string]*config.TeamConfig{"sig-foo": {ID: "T1"}},
+			want:     map[string]string{},
+		},
+		{
+			name: "renamed teams",
+			local: map[string]*config.TeamConfig{
+				"sig-bar": {ID: "T1", Children: map[string]*config.TeamConfig{
+					"sig-baz": {ID: "T2"},
+				}},
+			},
+			upstream: map[string]*config.TeamConfig{
+				"sig-foo": {ID: "T1", Children: map[string]*config.TeamConfig{
+					"sig-qux": {ID: "T2"},
+				}},
+			},
+			want: map[string]string{"sig-foo": "sig-bar", "sig-qux": "sig-baz"},
+		},
+		{
+			name:     "new team without an ID",
+			local:    map[string]*config.TeamConfig{"sig-bar": {}},
+			upstream: map[string]*config.TeamConfig{"sig-foo": {ID: "T1"}},
+			want:     map[string]string{},
+		},
+		{
+			name:     "copied team",
+			local:    map[string]*config.TeamConfig{"sig-foo": {ID: "T1"}, "sig-bar": {ID: "T1"}},
+			upstream: map[string]*config.TeamConfig{"sig-foo": {ID: "T1"}},
+			wantErr:  `teams "sig-foo" and "sig-bar" have the same ID "T1", remove the ID of the team that should be created`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localCfg := &config.Config{Teams: tt.local}
+			localCfg.IndexTeams()
+			upstreamCfg := &config.Config{Teams: tt.upstream}
+			upstreamCfg.IndexTeams()
+
+			got, err := teamRenames(localCfg, upstreamCfg)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("teamRenames() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("teamRenames() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("teamRenames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeamSlugs(t *testing.T) {
+	cfg := &config.Config{Teams: map[string]*config.TeamConfig{
+		"SIG Datapath": {Slug: "datapath"},
+		"SIG Policy":   {},
+	}}
+	cfg.IndexTeams()
+	tm := &Manager{}
+	tm.UseTeamSlugs(cfg)
+
+	for teamName, want := range map[string]string{
+		"SIG Datapath": "datapath",
+		"SIG Policy":   "sig-policy",
+	} {
+		if got := tm.teamSlug(teamName); got != want {
+			t.Errorf("teamSlug(%q) = %q, want %q", teamName, got, want)
+		}
+	}
+}
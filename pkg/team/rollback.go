@@ -0,0 +1,554 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gh "github.com/google/go-github/v79/github"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/cilium/team-manager/pkg/config"
+	"github.com/cilium/team-manager/pkg/github"
+	"github.com/cilium/team-manager/pkg/journal"
+	"github.com/cilium/team-manager/pkg/terminal"
+)
+
+// RollbackStep is an operation that reverts an operation recorded in the
+// journal.
+type RollbackStep struct {
+	// Reverts is the recorded operation being reverted.
+	Reverts journal.Operation
+	// Operation is the operation that reverts it.
+	Operation journal.Operation
+}
+
+// PlanRollback returns the steps that revert all operations of the given run,
+// in the reverse order they were applied, and the operations that can't be
+// reverted.
+func PlanRollback(run *journal.Run) (steps []RollbackStep, irreversible []journal.Operation) {
+	// Teams created by the run are deleted by the rollback, and teams deleted
+	// by the run can't be restored, so there's no point in reverting any other
+	// operation on them.
+	createdTeams := map[string]struct{}{}
+	deletedTeams := map[string]struct{}{}
+	for _, op := range run.Operations {
+		switch op.Kind {
+		case journal.KindCreateTeam:
+			createdTeams[op.Team] = struct{}{}
+		case journal.KindDeleteTeam:
+			deletedTeams[op.Team] = struct{}{}
+		}
+	}
+
+	for i := len(run.Operations) - 1; i >= 0; i-- {
+		op := run.Operations[i]
+		if _, ok := createdTeams[op.Team]; ok && op.Kind != journal.KindCreateTeam {
+			continue
+		}
+		if _, ok := deletedTeams[op.Team]; ok && op.Kind != journal.KindDeleteTeam {
+			continue
+		}
+
+		inverse := journal.Operation{
+			Team:       op.Team,
+			Member:     op.Member,
+			Repository: op.Repository,
+		}
+		switch op.Kind {
+		case journal.KindInviteOrgMember:
+			inverse.Kind = journal.KindRemoveOrgMember
+		case journal.KindRemoveOrgMember:
+			inverse.Kind = journal.KindInviteOrgMember
+		case journal.KindCreateTeam:
+			inverse.Kind = journal.KindDeleteTeam
+		case journal.KindDeleteTeam:
+			irreversible = append(irreversible, op)
+			continue
+		case journal.KindEditTeam:
+			if op.Previous == nil {
+				irreversible = append(irreversible, op)
+				continue
+			}
+			// The settings overwritten by the inverse operations of team
+			// settings are only known, and recorded, when applying them.
+			inverse.Kind = journal.KindEditTeam
+		case journal.KindRenameTeam:
+			if op.Previous == nil {
+				irreversible = append(irreversible, op)
+				continue
+			}
+			inverse.Kind = journal.KindRenameTeam
+			inverse.Team = op.Previous.Name
+			inverse.Previous = &journal.State{Name: op.Team}
+		case journal.KindAddTeamMember:
+			inverse.Kind = journal.KindRemoveTeamMember
+		case journal.KindRemoveTeamMember:
+			inverse.Kind = journal.KindAddTeamMember
+			if op.Previous != nil {
+				inverse.Role = op.Previous.Role
+			}
+		case journal.KindSetTeamMemberRole, journal.KindSetOrgMemberRole:
+			if op.Previous == nil {
+				irreversible = append(irreversible, op)
+				continue
+			}
+			inverse.Kind = op.Kind
+			inverse.Role = op.Previous.Role
+			inverse.Previous = &journal.State{Role: op.Role}
+		case journal.KindUpdateCodeReview:
+			if op.Previous == nil || op.Previous.CodeReviewAssignment == nil {
+				// The team didn't exist before this run.
+				continue
+			}
+			inverse.Kind = journal.KindUpdateCodeReview
+		case journal.KindSetTeamRepoPermission, journal.KindRemoveTeamRepoPermission,
+			journal.KindSetUserRepoPermission, journal.KindRemoveUserRepoPermission:
+			if op.Previous == nil {
+				irreversible = append(irreversible, op)
+				continue
+			}
+			isTeam := op.Kind == journal.KindSetTeamRepoPermission || op.Kind == journal.KindRemoveTeamRepoPermission
+			switch {
+			case op.Previous.Permission == "" && isTeam:
+				inverse.Kind = journal.KindRemoveTeamRepoPermission
+			case op.Previous.Permission == "":
+				inverse.Kind = journal.KindRemoveUserRepoPermission
+			case isTeam:
+				inverse.Kind = journal.KindSetTeamRepoPermission
+			default:
+				inverse.Kind = journal.KindSetUserRepoPermission
+			}
+			inverse.Permission = op.Previous.Permission
+			inverse.Previous = &journal.State{Permission: op.Permission}
+		default:
+			irreversible = append(irreversible, op)
+			continue
+		}
+		steps = append(steps, RollbackStep{Reverts: op, Operation: inverse})
+	}
+
+	return steps, irreversible
+}
+
+// RemainingRollbackSteps returns the given rollback steps left to apply after
+// the operations already applied by an unfinished run of the same rollback.
+// The applied operations must be the first steps of the rollback, in the same
+// order.
+func (tm *Manager) RemainingRollbackSteps(steps []RollbackStep, applied []journal.Operation) ([]RollbackStep, error) {
+	for i, op := range applied {
+		if i >= len(steps) {
+			return nil, fmt.Errorf("operation %q was not planned by the rollback", op)
+		}
+		// The previous state of some operations is only recorded when
+		// applying them.
+		planned := steps[i].Operation
+		planned.Previous, op.Previous = nil, nil
+		if planned != op {
+			return nil, fmt.Errorf("operation %q does not match the planned operation %q", op, planned)
+		}
+		if op.Kind == journal.KindRenameTeam {
+			// GitHub derived the new slug of the team from its new name.
+			delete(tm.slugs, steps[i].Operation.Previous.Name)
+		}
+	}
+	return steps[len(applied):], nil
+}
+
+// ConfirmRollback prints the given rollback steps and asks for confirmation
+// to apply them, unless force is set. Irreversible operations are only
+// printed, with the recorded state needed to recreate them manually. It
+// returns false if there is nothing to revert, on dry runs and if the user
+// declines.
+func ConfirmRollback(steps []RollbackStep, irreversible []journal.Operation, force, dryRun bool) (bool, error) {
+	if len(irreversible) != 0 {
+		fmt.Printf("The following operations can't be reverted:\n")
+		for _, op := range irreversible {
+			fmt.Printf("  %s\n", op)
+			if op.Kind == journal.KindDeleteTeam && op.Previous != nil {
+				printTeamState(op.Team, op.Previous, "    ")
+			}
+		}
+	}
+
+	if len(steps) == 0 {
+		fmt.Printf("Nothing to revert\n")
+		return false, nil
+	}
+
+	fmt.Printf("Going to submit the following changes:\n")
+	for _, step := range steps {
+		fmt.Printf("  %s\n", step.Operation)
+	}
+	if dryRun {
+		fmt.Printf("Skipping confirmation due to dry run. No changes will be made into GitHub\n")
+		return false, nil
+	}
+	if force {
+		return true, nil
+	}
+	return terminal.AskForConfirmation("Continue?")
+}
+
+// Rollback applies the given rollback steps into GitHub, which must have been
+// confirmed with ConfirmRollback.
+func (tm *Manager) Rollback(ctx context.Context, steps []RollbackStep) error {
+	for _, step := range steps {
+		switch step.Operation.Kind {
+		case journal.KindEditTeam, journal.KindUpdateCodeReview:
+			// Record the settings being overwritten so that the rollback can
+			// be reverted as well.
+			prev, err := tm.currentTeamState(ctx, step.Operation.Kind, step.Operation.Team)
+			if err != nil {
+				return err
+			}
+			step.Operation.Previous = prev
+		}
+		if err := tm.applyRollbackStep(ctx, step); err != nil {
+			return fmt.Errorf("unable to apply %q: %w", step.Operation, err)
+		}
+		if err := tm.record(step.Operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tm *Manager) applyRollbackStep(ctx context.Context, step RollbackStep) error {
+	op := step.Operation
+	fmt.Printf("Applying %s\n", op)
+
+	switch op.Kind {
+	case journal.KindRemoveOrgMember:
+		// Removing the membership also cancels the invitation of members that
+		// haven't accepted it yet.
+		_, err := tm.ghClient.Organizations.RemoveOrgMembership(ctx, op.Member, tm.owner)
+		return err
+
+	case journal.KindInviteOrgMember:
+		user, _, err := tm.ghClient.Users.Get(ctx, op.Member)
+		if err != nil {
+			return fmt.Errorf("unable to fetch information about user %q: %w", op.Member, err)
+		}
+		var teamIDs []int64
+		if step.Reverts.Previous != nil {
+			for _, teamName := range step.Reverts.Previous.Teams {
+				t, _, err := tm.ghClient.Teams.GetTeamBySlug(ctx, tm.owner, tm.teamSlug(teamName))
+				if err != nil {
+					fmt.Printf("[WARN] unable to re-invite %q into team %q: %s\n", op.Member, teamName, err)
+					continue
+				}
+				teamIDs = append(teamIDs, t.GetID())
+			}
+		}
+		opts := &gh.CreateOrgInvitationOptions{
+			InviteeID: user.ID,
+			TeamID:    teamIDs,
+		}
+		if step.Reverts.Previous != nil && step.Reverts.Previous.Role != "" {
+			opts.Role = gh.Ptr(config.OrgRole(step.Reverts.Previous.Role).InvitationRole())
+		}
+		_, _, err = tm.ghClient.Organizations.CreateOrgInvitation(ctx, tm.owner, opts)
+		return err
+
+	case journal.KindSetOrgMemberRole:
+		_, _, err := tm.ghClient.Organizations.EditOrgMembership(ctx, op.Member, tm.owner, &gh.Membership{
+			Role: gh.Ptr(op.Role),
+		})
+		return err
+
+	case journal.KindDeleteTeam:
+		_, err := tm.ghClient.Teams.DeleteTeamBySlug(ctx, tm.owner, tm.teamSlug(op.Team))
+		return err
+
+	case journal.KindEditTeam:
+		prev := step.Reverts.Previous
+		var parentTeamID *int64
+		if prev.ParentTeam != "" {
+			parent, _, err := tm.ghClient.Teams.GetTeamBySlug(ctx, tm.owner, tm.teamSlug(prev.ParentTeam))
+			if err != nil {
+				return fmt.Errorf("unable to get parent team %q: %w", prev.ParentTeam, err)
+			}
+			parentTeamID = parent.ID
+		}
+		_, _, err := tm.ghClient.Teams.EditTeamBySlug(ctx, tm.owner, tm.teamSlug(op.Team), gh.NewTeam{
+			Name:         op.Team,
+			Description:  &prev.Description,
+			ParentTeamID: parentTeamID,
+			Privacy:      prev.Privacy.RestPrivacy(),
+		}, parentTeamID == nil)
+		return err
+
+	case journal.KindRenameTeam:
+		t, _, err := tm.ghClient.Teams.EditTeamBySlug(ctx, tm.owner, tm.teamSlug(op.Previous.Name), gh.NewTeam{
+			Name: op.Team,
+		}, false)
+		if err != nil {
+			return err
+		}
+		delete(tm.slugs, op.Previous.Name)
+		tm.setTeamSlug(op.Team, t.GetSlug())
+		return nil
+
+	case journal.KindAddTeamMember, journal.KindSetTeamMemberRole:
+		role := op.Role
+		if role == "" {
+			role = "member"
+		}
+		_, _, err := tm.ghClient.Teams.AddTeamMembershipBySlug(ctx, tm.owner, tm.teamSlug(op.Team), op.Member, &gh.TeamAddTeamMembershipOptions{Role: role})
+		return err
+
+	case journal.KindRemoveTeamMember:
+		_, err := tm.ghClient.Teams.RemoveTeamMembershipBySlug(ctx, tm.owner, tm.teamSlug(op.Team), op.Member)
+		return err
+
+	case journal.KindUpdateCodeReview:
+		t, _, err := tm.ghClient.Teams.GetTeamBySlug(ctx, tm.owner, tm.teamSlug(op.Team))
+		if err != nil {
+			return fmt.Errorf("unable to get team %q: %w", op.Team, err)
+		}
+		cra := step.Reverts.Previous.CodeReviewAssignment
+		fmt.Printf("[WARN] members excluded from code review assignments of team %q can't be restored\n", op.Team)
+		return tm.pushCodeReviewAssignmentForTeam(ctx, t.GetNodeID(), github.UpdateTeamReviewAssignmentInput{
+			Algorithm:       cra.Algorithm,
+			Enabled:         githubv4.Boolean(cra.Enabled),
+			NotifyTeam:      githubv4.Boolean(cra.NotifyTeam),
+			TeamMemberCount: githubv4.Int(cra.TeamMemberCount),
+		})
+
+	case journal.KindSetTeamRepoPermission:
+		_, err := tm.ghClient.Teams.AddTeamRepoBySlug(ctx, tm.owner, tm.teamSlug(op.Team), tm.owner, op.Repository, &gh.TeamAddTeamRepoOptions{
+			Permission: config.GraphQLPerm2RestAPIPerm(op.Permission),
+		})
+		return err
+
+	case journal.KindRemoveTeamRepoPermission:
+		_, err := tm.ghClient.Teams.RemoveTeamRepoBySlug(ctx, tm.owner, tm.teamSlug(op.Team), tm.owner, op.Repository)
+		return err
+
+	case journal.KindSetUserRepoPermission:
+		_, _, err := tm.ghClient.Repositories.AddCollaborator(ctx, tm.owner, op.Repository, op.Member, &gh.RepositoryAddCollaboratorOptions{
+			Permission: config.GraphQLPerm2RestAPIPerm(op.Permission),
+		})
+		return err
+
+	case journal.KindRemoveUserRepoPermission:
+		_, err := tm.ghClient.Repositories.RemoveCollaborator(ctx, tm.owner, op.Repository, op.Member)
+		return err
+	}
+
+	return fmt.Errorf("unknown operation kind %q", op.Kind)
+}
+
+// printTeamState prints the recorded state of a team so that it can be
+// recreated manually.
+func printTeamState(teamName string, state *journal.State, indent string) {
+	fmt.Printf("%sTeam: %s\n", indent, teamName)
+	fmt.Printf("%s  Description: %s\n", indent, state.Description)
+	fmt.Printf("%s  Privacy: %s\n", indent, state.Privacy)
+	if state.ParentTeam != "" {
+		fmt.Printf("%s  Parent team: %s\n", indent, state.ParentTeam)
+	}
+	fmt.Printf("%s  Members: %s\n", indent, strings.Join(state.Members, ", "))
+	if len(state.Maintainers) != 0 {
+		fmt.Printf("%s  Maintainers: %s\n", indent, strings.Join(state.Maintainers, ", "))
+	}
+
+	repos := make([]string, 0, len(state.Repositories))
+	for repo := range state.Repositories {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		fmt.Printf("%s  Repository %q: %s\n", indent, repo, state.Repositories[repo])
+	}
+
+	children := make([]string, 0, len(state.Children))
+	for child := range state.Children {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	for _, child := range children {
+		printTeamState(child, state.Children[child], indent+"  ")
+	}
+}
+
+// previousTeamState returns the state of the given team in GitHub at the
+// beginning of the push.
+func (tm *Manager) previousTeamState(teamName string) *journal.State {
+	if tm.upstreamCfg == nil {
+		return nil
+	}
+	team, ok := tm.upstreamCfg.AllTeams[teamName]
+	if !ok {
+		return nil
+	}
+	return teamState(tm.upstreamCfg, teamName, team)
+}
+
+func teamState(cfg *config.Config, teamName string, team *config.TeamConfig) *journal.State {
+	cra := team.CodeReviewAssignment
+	state := &journal.State{
+		Description:          team.Description,
+		Privacy:              team.Privacy,
+		ParentTeam:           string(team.ParentTeam),
+		CodeReviewAssignment: &cra,
+		Members:              append([]string(nil), team.Members...),
+		Maintainers:          append([]string(nil), team.Maintainers...),
+		Repositories:         map[string]string{},
+		Children:             map[string]*journal.State{},
+	}
+	for repoName, repo := range cfg.Repositories {
+		_, teams := repoPermissions(repo)
+		if perm, ok := teams[config.TeamOrMemberName(teamName)]; ok {
+			state.Repositories[string(repoName)] = string(perm)
+		}
+	}
+	for childName, child := range team.Children {
+		state.Children[childName] = teamState(cfg, childName, child)
+	}
+	return state
+}
+
+// previousCodeReviewState returns the code review assignment of the given
+// team in GitHub at the beginning of the push.
+func (tm *Manager) previousCodeReviewState(teamName string) *journal.State {
+	if tm.upstreamCfg == nil {
+		return nil
+	}
+	team, ok := tm.upstreamCfg.AllTeams[teamName]
+	if !ok {
+		return nil
+	}
+	cra := team.CodeReviewAssignment
+	return &journal.State{CodeReviewAssignment: &cra}
+}
+
+// currentTeamState returns the settings of the given team in GitHub that are
+// changed by an operation of the given kind.
+func (tm *Manager) currentTeamState(ctx context.Context, kind journal.Kind, teamName string) (*journal.State, error) {
+	var q queryTeamSettingsResult
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(tm.owner),
+		"teamSlug":        githubv4.String(tm.teamSlug(teamName)),
+	}
+	if err := tm.gqlQuery(ctx, &q, variables); err != nil {
+		return nil, fmt.Errorf("failed to query settings of team %q: %w", teamName, err)
+	}
+	t := q.Organization.Team
+	if t == nil {
+		return nil, fmt.Errorf("team %q not found", teamName)
+	}
+
+	if kind == journal.KindUpdateCodeReview {
+		var cra config.CodeReviewAssignment
+		if t.ReviewRequestDelegationEnabled {
+			cra = config.CodeReviewAssignment{
+				Algorithm:       config.TeamReviewAssignmentAlgorithm(t.ReviewRequestDelegationAlgorithm),
+				Enabled:         bool(t.ReviewRequestDelegationEnabled),
+				NotifyTeam:      bool(t.ReviewRequestDelegationNotifyTeam),
+				TeamMemberCount: int(t.ReviewRequestDelegationMemberCount),
+			}
+		}
+		return &journal.State{CodeReviewAssignment: &cra}, nil
+	}
+	return &journal.State{
+		Description: string(t.Description),
+		Privacy:     config.TeamPrivacy(t.Privacy),
+		ParentTeam:  string(t.ParentTeam.Name),
+	}, nil
+}
+
+// queryTeamSettingsResult was derived from
+//
+//	query organization {
+//	  organization(login: "$repositoryOwner") {
+//	    team(slug: "$teamSlug") {
+//	      description
+//	      privacy
+//	      parentTeam {
+//	        name
+//	      }
+//	      reviewRequestDelegationEnabled
+//	      reviewRequestDelegationAlgorithm
+//	      reviewRequestDelegationMemberCount
+//	      reviewRequestDelegationNotifyTeam
+//	    }
+//	  }
+//	}
+type queryTeamSettingsResult struct {
+	Organization struct {
+		Team *struct {
+			Description githubv4.String
+			Privacy     githubv4.TeamPrivacy
+			ParentTeam  struct {
+				Name githubv4.String
+			}
+			ReviewRequestDelegationEnabled     githubv4.Boolean
+			ReviewRequestDelegationAlgorithm   githubv4.String
+			ReviewRequestDelegationMemberCount githubv4.Int
+			ReviewRequestDelegationNotifyTeam  githubv4.Boolean
+		} `graphql:"team(slug: $teamSlug)"`
+	} `graphql:"organization(login: $repositoryOwner)"`
+}
+
+// previousMemberState returns the teams the given member belonged to in
+// GitHub at the beginning of the push.
+func (tm *Manager) previousMemberState(login string) *journal.State {
+	if tm.upstreamCfg == nil {
+		return nil
+	}
+	state := &journal.State{
+		Role: string(tm.upstreamCfg.Members[login].Role),
+	}
+	for teamName, team := range tm.upstreamCfg.AllTeams {
+		for _, member := range team.Members {
+			if member == login {
+				state.Teams = append(state.Teams, teamName)
+				break
+			}
+		}
+	}
+	sort.Strings(state.Teams)
+	return state
+}
+
+// previousTeamMemberState returns the role of the given member of the given
+// team in GitHub at the beginning of the push.
+func (tm *Manager) previousTeamMemberState(teamName, login string) *journal.State {
+	if tm.upstreamCfg == nil {
+		return nil
+	}
+	team, ok := tm.upstreamCfg.AllTeams[teamName]
+	if !ok {
+		return nil
+	}
+	role := "member"
+	for _, maintainer := range team.Maintainers {
+		if maintainer == login {
+			role = "maintainer"
+			break
+		}
+	}
+	return &journal.State{Role: role}
+}
+
+// previousRepoState returns the permission of the given user or team in the
+// given repository in GitHub at the beginning of the push.
+func (tm *Manager) previousRepoState(repoName, userOrTeam string, isUser bool) *journal.State {
+	if tm.upstreamCfg == nil {
+		return nil
+	}
+	users, teams := repoPermissions(tm.upstreamCfg.Repositories[config.RepositoryName(repoName)])
+	perms := teams
+	if isUser {
+		perms = users
+	}
+	return &journal.State{
+		Permission: perms[config.TeamOrMemberName(userOrTeam)].GetPermission(),
+	}
+}
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package team
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v79/github"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/cilium/team-manager/pkg/config"
+)
+
+// Target is the entity of the organization affected by an event, such as a
+// webhook. Empty fields are not affected.
+type Target struct {
+	// Team is the name of the team.
+	Team string `json:"team,omitempty"`
+
+	// TeamSlug is the slug of the team. If empty, it's derived from the
+	// local configuration.
+	TeamSlug string `json:"teamSlug,omitempty"`
+
+	// Member is the login of the member or outside collaborator.
+	Member string `json:"member,omitempty"`
+
+	// Repository is the name of the repository.
+	Repository string `json:"repository,omitempty"`
+}
+
+func (t Target) String() string {
+	var parts []string
+	if t.Team != "" {
+		parts = append(parts, fmt.Sprintf("team %q", t.Team))
+	}
+	if t.Member != "" {
+		parts = append(parts, fmt.Sprintf("member %q", t.Member))
+	}
+	if t.Repository != "" {
+		parts = append(parts, fmt.Sprintf("repository %q", t.Repository))
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// IsZero returns true if the target doesn't affect any entity.
+func (t Target) IsZero() bool {
+	return t.Team == "" && t.Member == "" && t.Repository == ""
+}
+
+// DiffTarget fetches only the entities of the target from GitHub and returns
+// how they drifted from the local configuration, one change per line. The
+// membership of a member in a team is checked with the rest of the team.
+func (tm *Manager) DiffTarget(ctx context.Context, localCfg *config.Config, target Target) ([]string, error) {
+	var drift []string
+	if target.Team != "" {
+		d, err := tm.diffTeamTarget(ctx, localCfg, target)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, d...)
+	} else if target.Member != "" {
+		d, err := tm.diffMemberTarget(ctx, localCfg, target.Member)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, d...)
+	}
+	if target.Repository != "" {
+		d, err := tm.diffRepositoryTarget(ctx, localCfg, target.Repository)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, d...)
+	}
+	return drift, nil
+}
+
+// queryTeamMembersResult was derived from
+//
+//	query organization {
+//	  organization(login: "$repositoryOwner") {
+//	    team(slug: "$teamSlug") {
+//	      members(first: 100, after: $membersCursor, membership: IMMEDIATE) {
+//	        edges {
+//	          role
+//	          node {
+//	            login
+//	          }
+//	        }
+//	        pageInfo {
+//	          hasNextPage
+//	          endCursor
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type queryTeamMembersResult struct {
+	Organization struct {
+		Team *struct {
+			Members struct {
+				Edges []struct {
+					Role githubv4.TeamMemberRole
+					Node struct {
+						Login githubv4.String
+					}
+				}
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage githubv4.Boolean
+				}
+			} `graphql:"members(first: 100, after: $membersCursor, membership: IMMEDIATE)"`
+		} `graphql:"team(slug: $teamSlug)"`
+	} `graphql:"organization(login: $repositoryOwner)"`
+}
+
+func (tm *Manager) diffTeamTarget(ctx context.Context, localCfg *config.Config, target Target) ([]string, error) {
+	slug := target.TeamSlug
+	if slug == "" {
+		slug = localCfg.TeamSlug(target.Team)
+	}
+
+	var members, maintainers []string
+	exists := true
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(tm.owner),
+		"teamSlug":        githubv4.String(slug),
+		"membersCursor":   (*githubv4.String)(nil), // Null after argument to get first page.
+	}
+	for {
+		var q queryTeamMembersResult
+		if err := tm.gqlQuery(ctx, &q, variables); err != nil {
+			return nil, fmt.Errorf("failed to query members of team %q: %w", target.Team, err)
+		}
+		if q.Organization.Team == nil {
+			exists = false
+			break
+		}
+		for _, edge := range q.Organization.Team.Members.Edges {
+			login := string(edge.Node.Login)
+			members = append(members, login)
+			if edge.Role == githubv4.TeamMemberRoleMaintainer {
+				maintainers = append(maintainers, login)
+			}
+		}
+		if !q.Organization.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		variables["membersCursor"] = githubv4.NewString(q.Organization.Team.Members.PageInfo.EndCursor)
+	}
+
+	team, ok := localCfg.AllTeams[target.Team]
+	switch {
+	case !ok && !exists:
+		return nil, nil
+	case !ok:
+		return []string{fmt.Sprintf("team %q exists in GitHub but not in the local configuration", target.Team)}, nil
+	case !exists:
+		return []string{fmt.Sprintf("team %q does not exist in GitHub", target.Team)}, nil
+	}
+
+	now := time.Now()
+	drift := diffLogins(fmt.Sprintf("team %q: member", target.Team), team.ActiveMembers(now), members)
+	drift = append(drift, diffLogins(fmt.Sprintf("team %q: maintainer", target.Team), team.ActiveMaintainers(now), maintainers)...)
+	return drift, nil
+}
+
+func (tm *Manager) diffMemberTarget(ctx context.Context, localCfg *config.Config, login string) ([]string, error) {
+	membership, resp, err := tm.ghClient.Organizations.GetOrgMembership(ctx, login, tm.owner)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return nil, fmt.Errorf("failed to get organization membership of %q: %w", login, err)
+	}
+	upstreamMember := err == nil && membership.GetState() == "active"
+
+	localMember, ok := localCfg.Members[login]
+	switch {
+	case !ok && !upstreamMember:
+		return nil, nil
+	case !ok:
+		return []string{fmt.Sprintf("member %q was added to the organization in GitHub", login)}, nil
+	case !upstreamMember && membership.GetState() == "pending":
+		// The member was invited but did not accept the invitation yet.
+		return nil, nil
+	case !upstreamMember:
+		return []string{fmt.Sprintf("member %q was removed from the organization in GitHub", login)}, nil
+	}
+	if localMember.Role != "" && string(localMember.Role) != membership.GetRole() {
+		return []string{fmt.Sprintf("member %q has the role %q in GitHub instead of %q", login, membership.GetRole(), localMember.Role)}, nil
+	}
+	return nil, nil
+}
+
+func (tm *Manager) diffRepositoryTarget(ctx context.Context, localCfg *config.Config, repoName string) ([]string, error) {
+	upstream := config.Repository{}
+
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		teams, resp, err := tm.ghClient.Repositories.ListTeams(ctx, tm.owner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list teams of repository %q: %w", repoName, err)
+		}
+		for _, t := range teams {
+			perm := restPermission(t.GetPermission())
+			upstream[perm] = append(upstream[perm], config.TeamOrMemberName(t.GetName()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	collabOpts := &gh.ListCollaboratorsOptions{Affiliation: "direct", ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := tm.ghClient.Repositories.ListCollaborators(ctx, tm.owner, repoName, collabOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collaborators of repository %q: %w", repoName, err)
+		}
+		for _, u := range users {
+			perm := restPermission(u.GetRoleName())
+			perm.SetUser()
+			upstream[perm] = append(upstream[perm], config.TeamOrMemberName(u.GetLogin()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		collabOpts.Page = resp.NextPage
+	}
+
+	local := localCfg.Repositories[config.RepositoryName(repoName)]
+	localPerms := targetPermissions(localCfg, local)
+	upstreamPerms := targetPermissions(localCfg, upstream)
+
+	var names []string
+	for name := range localPerms {
+		names = append(names, name)
+	}
+	for name := range upstreamPerms {
+		if _, ok := localPerms[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var drift []string
+	for _, name := range names {
+		localPerm, upstreamPerm := localPerms[name], upstreamPerms[name]
+		switch {
+		case localPerm == upstreamPerm:
+		case upstreamPerm == "":
+			drift = append(drift, fmt.Sprintf("repository %q: %s lost the %s permission in GitHub", repoName, name, localPerm))
+		case localPerm == "":
+			drift = append(drift, fmt.Sprintf("repository %q: %s was given the %s permission in GitHub", repoName, name, upstreamPerm))
+		default:
+			drift = append(drift, fmt.Sprintf("repository %q: %s has the %s permission in GitHub instead of %s", repoName, name, upstreamPerm, localPerm))
+		}
+	}
+	return drift, nil
+}
+
+// targetPermissions returns the permission of each team and user of the
+// repository, keyed by "team NAME" and "user LOGIN". Teams with the same
+// permission as one of their ancestors are skipped, as SortConfig does.
+func targetPermissions(cfg *config.Config, repo config.Repository) map[string]config.Permission {
+	perms := map[string]config.Permission{}
+	for perm, names := range repo {
+		for _, name := range names {
+			if perm.IsUser() {
+				perms["user "+string(name)] = config.Permission(perm.GetPermission())
+				continue
+			}
+			if ancestorHasPermission(cfg, repo, string(name), perm) {
+				continue
+			}
+			perms["team "+string(name)] = perm
+		}
+	}
+	return perms
+}
+
+func ancestorHasPermission(cfg *config.Config, repo config.Repository, teamName string, perm config.Permission) bool {
+	team, ok := cfg.AllTeams[teamName]
+	for ok && team.ParentTeam != "" {
+		if slices.Contains(repo[perm], team.ParentTeam) {
+			return true
+		}
+		team, ok = cfg.AllTeams[string(team.ParentTeam)]
+	}
+	return false
+}
+
+// restPermission converts a permission of the REST API, e.g. "push", into
+// the permission used in the configuration, e.g. "WRITE".
+func restPermission(perm string) config.Permission {
+	switch perm {
+	case "pull":
+		return config.Permission(githubv4.RepositoryPermissionRead)
+	case "push":
+		return config.Permission(githubv4.RepositoryPermissionWrite)
+	default:
+		return config.Permission(strings.ToUpper(perm))
+	}
+}
+
+// diffLogins returns the logins added or removed in GitHub.
+func diffLogins(what string, local, upstream []string) []string {
+	var drift []string
+	for _, login := range upstream {
+		if !slices.Contains(local, login) {
+			drift = append(drift, fmt.Sprintf("%s %q was added in GitHub", what, login))
+		}
+	}
+	for _, login := range local {
+		if !slices.Contains(upstream, login) {
+			drift = append(drift, fmt.Sprintf("%s %q was removed in GitHub", what, login))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
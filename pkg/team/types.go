@@ -94,6 +94,11 @@ type teamMember struct {
 	Name  githubv4.String
 }
 
+type orgMemberEdge struct {
+	Role githubv4.OrganizationMemberRole
+	Node teamMember
+}
+
 // queryResultMembers was derived from
 //
 //	query organization {
@@ -104,10 +109,13 @@ type teamMember struct {
 //	        endCursor
 //	        hasNextPage
 //	      }
-//	      nodes {
-//	        login
-//	        id
-//	        name
+//	      edges {
+//	        role
+//	        node {
+//	          login
+//	          id
+//	          name
+//	        }
 //	      }
 //	    }
 //	  }
@@ -116,7 +124,7 @@ type queryResultMembers struct {
 	Organization struct {
 		Members struct {
 			TotalCount githubv4.Int
-			Nodes      []teamMember
+			Edges      []orgMemberEdge
 			PageInfo   struct {
 				EndCursor   githubv4.String
 				HasNextPage githubv4.Boolean
@@ -150,6 +158,7 @@ type teamRepositories struct {
 
 type teamMembers struct {
 	teamCommon
+	Slug        githubv4.String
 	Description githubv4.String
 	DatabaseId  int64
 	Privacy     githubv4.TeamPrivacy
@@ -161,8 +170,11 @@ type teamMembers struct {
 	ReviewRequestDelegationMemberCount githubv4.Int
 	ReviewRequestDelegationNotifyTeam  githubv4.Boolean
 	Members                            struct {
-		Nodes []struct {
-			Login githubv4.String
+		Edges []struct {
+			Role githubv4.TeamMemberRole
+			Node struct {
+				Login githubv4.String
+			}
 		}
 		PageInfo struct {
 			EndCursor   githubv4.String
@@ -247,6 +259,7 @@ type teamMembersGraphQL struct {
 //	    teams(first: 50, after: $teamsCursor) {
 //	      nodes {
 //	        name
+//	        slug
 //	        privacy
 //	        description
 //	        parentTeam {
@@ -257,8 +270,11 @@ type teamMembersGraphQL struct {
 //	            hasNextPage
 //	            endCursor
 //	          }
-//	          nodes {
-//	            login
+//	          edges {
+//	            role
+//	            node {
+//	              login
+//	            }
 //	          }
 //	        }
 //	        id
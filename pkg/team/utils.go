@@ -16,8 +16,6 @@ package team
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
 
 	"github.com/cilium/team-manager/pkg/config"
 
@@ -60,16 +58,29 @@ func getExcludedUsers(teamName string, members map[string]config.User, mentors [
 	return memberIDs
 }
 
-// slug returns the slug version of the team name. This simply replaces all
-// characters that are not in the following regex `[^a-z0-9]+` with a `-`.
-// It's a simplistic versions of the official's GitHub slug transformation since
-// GitHub changes accents characters as well, for example 'ä' to 'a'.
-func slug(s string) string {
-	s = strings.ToLower(s)
+// UseTeamSlugs makes the manager refer to the teams of the given configuration
+// by the slugs stored in it.
+func (tm *Manager) UseTeamSlugs(cfg *config.Config) {
+	for teamName, team := range cfg.AllTeams {
+		if team.Slug != "" {
+			tm.setTeamSlug(teamName, team.Slug)
+		}
+	}
+}
 
-	re := regexp.MustCompile("[^a-z0-9]+")
-	s = re.ReplaceAllString(s, "-")
+func (tm *Manager) setTeamSlug(teamName, teamSlug string) {
+	if tm.slugs == nil {
+		tm.slugs = map[string]string{}
+	}
+	tm.slugs[teamName] = teamSlug
+}
 
-	s = strings.Trim(s, "-")
-	return s
+// teamSlug returns the GitHub slug of the given team. Teams with an unknown
+// slug, for example teams that were never pulled from GitHub, fall back to
+// the slug computed from their name.
+func (tm *Manager) teamSlug(teamName string) string {
+	if teamSlug, ok := tm.slugs[teamName]; ok {
+		return teamSlug
+	}
+	return config.Slugify(teamName)
 }
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package webhook receives the GitHub webhooks of the organization, team,
+// membership, member and repository events and maps them to the affected
+// entities.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cilium/team-manager/pkg/team"
+)
+
+const (
+	// EventHeader is the header with the type of the event.
+	EventHeader = "X-GitHub-Event"
+	// SignatureHeader is the header with the HMAC-SHA256 signature of the
+	// payload.
+	SignatureHeader = "X-Hub-Signature-256"
+	// DeliveryHeader is the header with the unique ID of the delivery.
+	DeliveryHeader = "X-GitHub-Delivery"
+
+	// maxPayloadSize is the maximum size of the payloads sent by GitHub.
+	maxPayloadSize = 25 << 20
+)
+
+// ErrIgnored is returned by Parse for the events that don't affect the
+// configuration, such as pings.
+var ErrIgnored = errors.New("event ignored")
+
+// Event is an event received from GitHub.
+type Event struct {
+	// Type is the type of the event, e.g. "membership".
+	Type string `json:"type"`
+
+	// Action is the action of the event, e.g. "added".
+	Action string `json:"action,omitempty"`
+
+	// Organization is the login of the organization of the event.
+	Organization string `json:"organization,omitempty"`
+
+	// Target is the entity affected by the event.
+	Target team.Target `json:"target"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s.%s affecting %s", e.Type, e.Action, e.Target)
+}
+
+// Sign returns the signature of the payload with the given secret, in the
+// format of the X-Hub-Signature-256 header.
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that signature is the signature of the payload with the given
+// secret.
+func Verify(secret, payload []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("missing %s header", SignatureHeader)
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("unsupported signature %q", signature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, payload))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// payload contains the fields of the payloads used to map them to entities.
+type payload struct {
+	Action       string `json:"action"`
+	Organization struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+	Team *struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"team"`
+	Repository *struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+	// Member is the user of the membership and member events.
+	Member *struct {
+		Login string `json:"login"`
+	} `json:"member"`
+	// Membership is the membership of the organization events.
+	Membership *struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"membership"`
+	// Invitation is the invitation of the organization events.
+	Invitation *struct {
+		Login string `json:"login"`
+	} `json:"invitation"`
+	// Scope is the scope of the membership events, "team" or
+	// "organization".
+	Scope string `json:"scope"`
+}
+
+// Parse maps the payload of an event of the given type to the affected
+// entities. Events that don't affect the configuration return ErrIgnored.
+func Parse(eventType string, data []byte) (Event, error) {
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Event{}, fmt.Errorf("invalid %s payload: %w", eventType, err)
+	}
+
+	e := Event{Type: eventType, Action: p.Action, Organization: p.Organization.Login}
+	switch eventType {
+	case "organization":
+		// member_added, member_removed, member_invited and renamed.
+		switch {
+		case p.Membership != nil:
+			e.Target.Member = p.Membership.User.Login
+		case p.Invitation != nil:
+			e.Target.Member = p.Invitation.Login
+		}
+	case "team":
+		// created, deleted, edited, added_to_repository and
+		// removed_from_repository.
+		if p.Team != nil {
+			e.Target.Team, e.Target.TeamSlug = p.Team.Name, p.Team.Slug
+		}
+		if p.Repository != nil && (p.Action == "added_to_repository" || p.Action == "removed_from_repository" || p.Action == "edited") {
+			e.Target.Repository = p.Repository.Name
+		}
+	case "membership":
+		// added and removed, in the team scope.
+		if p.Scope != "" && p.Scope != "team" {
+			return e, ErrIgnored
+		}
+		if p.Team != nil {
+			e.Target.Team, e.Target.TeamSlug = p.Team.Name, p.Team.Slug
+		}
+		if p.Member != nil {
+			e.Target.Member = p.Member.Login
+		}
+	case "member":
+		// added, edited and removed outside collaborators of repositories.
+		if p.Member != nil {
+			e.Target.Member = p.Member.Login
+		}
+		if p.Repository != nil {
+			e.Target.Repository = p.Repository.Name
+		}
+	case "repository":
+		// created, deleted, renamed, transferred, archived, etc.
+		if p.Repository != nil {
+			e.Target.Repository = p.Repository.Name
+		}
+	default:
+		return e, ErrIgnored
+	}
+
+	if e.Target.IsZero() {
+		return e, ErrIgnored
+	}
+	return e, nil
+}
+
+// Handler is an HTTP handler receiving the webhooks of GitHub.
+type Handler struct {
+	// Secret is the secret of the webhook, used to verify the signature of
+	// the payloads.
+	Secret []byte
+
+	// Organization is the organization whose events are accepted. Events of
+	// other organizations are ignored.
+	Organization string
+
+	// OnEvent is called with every event affecting the configuration. It
+	// must not block, the response is sent once it returns.
+	OnEvent func(Event)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxPayloadSize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := Verify(h.Secret, data, r.Header.Get(SignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	e, err := Parse(r.Header.Get(EventHeader), data)
+	switch {
+	case errors.Is(err, ErrIgnored):
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case h.Organization != "" && e.Organization != "" && !strings.EqualFold(e.Organization, h.Organization):
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.OnEvent(e)
+	w.WriteHeader(http.StatusAccepted)
+}
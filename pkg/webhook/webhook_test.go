@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package webhook
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cilium/team-manager/pkg/team"
+)
+
+var secret = []byte("It's a Secret to Everybody")
+
+// replay sends the payload of the given event to h, signed with signature,
+// and returns the response and the events passed to OnEvent.
+func replay(t *testing.T, h *Handler, event string, payload []byte, signature string) (*httptest.ResponseRecorder, []Event) {
+	t.Helper()
+	var events []Event
+	h.OnEvent = func(e Event) { events = append(events, e) }
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, events
+}
+
+func readPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestHandlerReplay(t *testing.T) {
+	tests := []struct {
+		file       string
+		event      string
+		wantStatus int
+		want       Event
+	}{
+		{
+			file:       "team_added_to_repository.json",
+			event:      "team",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "team", Action: "added_to_repository", Organization: "cilium",
+				Target: team.Target{Team: "Cilium Teams", TeamSlug: "cilium-teams", Repository: "cilium"}},
+		},
+		{
+			file:       "team_edited.json",
+			event:      "team",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "team", Action: "edited", Organization: "cilium",
+				Target: team.Target{Team: "ebpf", TeamSlug: "ebpf"}},
+		},
+		{
+			file:       "membership_removed.json",
+			event:      "membership",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "membership", Action: "removed", Organization: "cilium",
+				Target: team.Target{Team: "ebpf", TeamSlug: "ebpf", Member: "joestringer"}},
+		},
+		{
+			file:       "member_added.json",
+			event:      "member",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "member", Action: "added", Organization: "cilium",
+				Target: team.Target{Member: "ciliumbot", Repository: "cilium"}},
+		},
+		{
+			file:       "repository_deleted.json",
+			event:      "repository",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "repository", Action: "deleted", Organization: "cilium",
+				Target: team.Target{Repository: "hubble"}},
+		},
+		{
+			file:       "organization_member_added.json",
+			event:      "organization",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "organization", Action: "member_added", Organization: "cilium",
+				Target: team.Target{Member: "borkmann"}},
+		},
+		{
+			file:       "organization_member_invited.json",
+			event:      "organization",
+			wantStatus: http.StatusAccepted,
+			want: Event{Type: "organization", Action: "member_invited", Organization: "cilium",
+				Target: team.Target{Member: "newcomer"}},
+		},
+		{
+			file:       "ping.json",
+			event:      "ping",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			file:       "team_edited.json",
+			event:      "pull_request",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			file:       "membership_other_org.json",
+			event:      "membership",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event+"/"+tt.file, func(t *testing.T) {
+			payload := readPayload(t, tt.file)
+			h := &Handler{Secret: secret, Organization: "cilium"}
+			rec, events := replay(t, h, tt.event, payload, Sign(secret, payload))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				if len(events) != 0 {
+					t.Fatalf("OnEvent called with %v for an ignored event", events)
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("OnEvent called %d times, want 1", len(events))
+			}
+			if events[0] != tt.want {
+				t.Errorf("OnEvent(%+v), want %+v", events[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerSignature(t *testing.T) {
+	payload := readPayload(t, "membership_removed.json")
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing", signature: ""},
+		{name: "wrong secret", signature: Sign([]byte("wrong"), payload)},
+		{name: "sha1", signature: "sha1=7d38cdd689735b008b3c702edd92eea23791c5f6"},
+		{name: "other payload", signature: Sign(secret, append(payload, ' '))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{Secret: secret}
+			rec, events := replay(t, h, "membership", payload, tt.signature)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if len(events) != 0 {
+				t.Errorf("OnEvent called for a payload with an invalid signature")
+			}
+		})
+	}
+}
+
+func TestHandlerPayloadTooLarge(t *testing.T) {
+	payload := bytes.Repeat([]byte(" "), maxPayloadSize+1)
+	h := &Handler{Secret: secret}
+	rec, events := replay(t, h, "membership", payload, Sign(secret, payload))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if len(events) != 0 {
+		t.Errorf("OnEvent called for a payload too large")
+	}
+}
+
+func TestHandlerMethod(t *testing.T) {
+	h := &Handler{Secret: secret, OnEvent: func(Event) { t.Error("OnEvent called for a GET request") }}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHandlerInvalidPayload(t *testing.T) {
+	payload := []byte(`{"action": `)
+	h := &Handler{Secret: secret}
+	rec, _ := replay(t, h, "team", payload, Sign(secret, payload))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestParseMembershipOrganizationScope(t *testing.T) {
+	_, err := Parse("membership", []byte(`{"action": "added", "scope": "organization", "member": {"login": "aanm"}}`))
+	if err != ErrIgnored {
+		t.Errorf("Parse() error = %v, want %v", err, ErrIgnored)
+	}
+}